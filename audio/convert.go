@@ -0,0 +1,166 @@
+package audio
+
+import (
+	"io"
+)
+
+// converter converts a stream of audio from one Format to another. It keeps any incomplete frame between
+// calls as well as the resampling state, so audio can be converted in arbitrarily sized chunks.
+type converter struct {
+	from, to   Format
+	pending    []byte
+	resamplers []*resampler
+}
+
+func newConverter(from, to Format) (*converter, error) {
+	if err := from.validate(); err != nil {
+		return nil, err
+	}
+	if err := to.validate(); err != nil {
+		return nil, err
+	}
+	c := &converter{from: from, to: to}
+	if from.SampleRate != to.SampleRate {
+		for i := 0; i < to.channels(); i++ {
+			c.resamplers = append(c.resamplers, newResampler(from.SampleRate, to.SampleRate))
+		}
+	}
+	return c, nil
+}
+
+func (c *converter) convert(p []byte) []byte {
+	data := p
+	if len(c.pending) > 0 {
+		data = append(c.pending, p...)
+	}
+	n := len(data) - len(data)%c.from.frameSize()
+	c.pending = append([]byte(nil), data[n:]...)
+	data = data[:n]
+
+	var samples []int16
+	switch c.from.Encoding {
+	case PCM16:
+		samples = BytesToSamples(data)
+	case ULaw:
+		samples = DecodeULaw(data)
+	case ALaw:
+		samples = DecodeALaw(data)
+	}
+
+	switch {
+	case c.from.channels() == 2 && c.to.channels() == 1:
+		samples = StereoToMono(samples)
+	case c.from.channels() == 1 && c.to.channels() == 2:
+		samples = MonoToStereo(samples)
+	}
+
+	if c.resamplers != nil {
+		samples = c.resample(samples)
+	}
+
+	switch c.to.Encoding {
+	case ULaw:
+		return EncodeULaw(samples)
+	case ALaw:
+		return EncodeALaw(samples)
+	}
+	return SamplesToBytes(samples)
+}
+
+func (c *converter) resample(samples []int16) []int16 {
+	channels := len(c.resamplers)
+	if channels == 1 {
+		return c.resamplers[0].process(samples)
+	}
+	perChannel := make([][]int16, channels)
+	for ch := range perChannel {
+		in := make([]int16, 0, len(samples)/channels)
+		for i := ch; i < len(samples); i += channels {
+			in = append(in, samples[i])
+		}
+		perChannel[ch] = c.resamplers[ch].process(in)
+	}
+	n := len(perChannel[0])
+	for _, s := range perChannel[1:] {
+		if len(s) < n {
+			n = len(s)
+		}
+	}
+	out := make([]int16, 0, n*channels)
+	for i := 0; i < n; i++ {
+		for ch := range perChannel {
+			out = append(out, perChannel[ch][i])
+		}
+	}
+	return out
+}
+
+// Convert converts audio from one Format to another, e.g. ulaw_8000 audio to 16kHz linear PCM.
+//
+// It returns the converted audio, or an error if either format is not supported.
+func Convert(data []byte, from, to Format) ([]byte, error) {
+	c, err := newConverter(from, to)
+	if err != nil {
+		return nil, err
+	}
+	return c.convert(data), nil
+}
+
+type convertReader struct {
+	r   io.Reader
+	c   *converter
+	buf []byte
+	out []byte
+	err error
+}
+
+// NewReader returns an io.Reader that reads audio of Format 'from' from r and converts it to Format 'to'.
+//
+// It returns an error if either format is not supported.
+func NewReader(r io.Reader, from, to Format) (io.Reader, error) {
+	c, err := newConverter(from, to)
+	if err != nil {
+		return nil, err
+	}
+	return &convertReader{r: r, c: c, buf: make([]byte, 4096)}, nil
+}
+
+func (cr *convertReader) Read(p []byte) (int, error) {
+	for len(cr.out) == 0 {
+		if cr.err != nil {
+			return 0, cr.err
+		}
+		n, err := cr.r.Read(cr.buf)
+		cr.out = cr.c.convert(cr.buf[:n])
+		cr.err = err
+	}
+	n := copy(p, cr.out)
+	cr.out = cr.out[n:]
+	return n, nil
+}
+
+type convertWriter struct {
+	w io.Writer
+	c *converter
+}
+
+// NewWriter returns an io.Writer that converts audio of Format 'from' written to it to Format 'to' and
+// writes it to w. It can be passed to TextToSpeechStream to convert audio as it is being streamed.
+//
+// Incomplete frames are buffered until the rest of the frame is written. It returns an error if either
+// format is not supported.
+func NewWriter(w io.Writer, from, to Format) (io.Writer, error) {
+	c, err := newConverter(from, to)
+	if err != nil {
+		return nil, err
+	}
+	return &convertWriter{w: w, c: c}, nil
+}
+
+func (cw *convertWriter) Write(p []byte) (int, error) {
+	out := cw.c.convert(p)
+	if _, err := cw.w.Write(out); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
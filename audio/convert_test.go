@@ -0,0 +1,99 @@
+package audio_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/haguro/elevenlabs-go/audio"
+)
+
+func testTone(n int) []int16 {
+	samples := make([]int16, n)
+	for i := range samples {
+		samples[i] = int16((i%50 - 25) * 1000)
+	}
+	return samples
+}
+
+func TestConvert(t *testing.T) {
+	samples := testTone(800)
+	ulaw := audio.EncodeULaw(samples)
+
+	pcm, err := audio.Convert(ulaw, audio.FormatULaw8000, audio.FormatPCM8000)
+	if err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+	if !bytes.Equal(pcm, audio.SamplesToBytes(audio.DecodeULaw(ulaw))) {
+		t.Error("Expected μ-law to PCM conversion to match DecodeULaw")
+	}
+
+	back, err := audio.Convert(pcm, audio.FormatPCM8000, audio.FormatULaw8000)
+	if err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+	if !bytes.Equal(back, ulaw) {
+		t.Error("Expected PCM to μ-law conversion to restore the original μ-law audio")
+	}
+
+	stereo16k := audio.Format{Encoding: audio.PCM16, SampleRate: 16000, Channels: 2}
+	out, err := audio.Convert(ulaw, audio.FormatULaw8000, stereo16k)
+	if err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+	if got, exp := len(out), len(ulaw)*2*2*2; got < exp-8 || got > exp {
+		t.Errorf("Expected about %d bytes of 16kHz stereo PCM, got %d", exp, got)
+	}
+
+	_, err = audio.Convert(pcm, audio.Format{Encoding: audio.PCM16, SampleRate: 8000, Channels: 6}, audio.FormatPCM8000)
+	if !errors.Is(err, audio.ErrUnsupportedFormat) {
+		t.Errorf("Expected error %q, got %v", audio.ErrUnsupportedFormat, err)
+	}
+}
+
+func TestStreamingConversion(t *testing.T) {
+	samples := testTone(2000)
+	src := audio.SamplesToBytes(samples)
+	from := audio.Format{Encoding: audio.PCM16, SampleRate: 24000, Channels: 1}
+	to := audio.Format{Encoding: audio.ALaw, SampleRate: 8000, Channels: 1}
+	exp, err := audio.Convert(src, from, to)
+	if err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+
+	t.Run("reader", func(t *testing.T) {
+		r, err := audio.NewReader(iotest.OneByteReader(bytes.NewReader(src)), from, to)
+		if err != nil {
+			t.Fatalf("Expected no errors, got %q", err)
+		}
+		got, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("Expected no errors, got %q", err)
+		}
+		if !bytes.Equal(got, exp) {
+			t.Errorf("Expected streamed conversion to match Convert (%d bytes), got %d bytes", len(exp), len(got))
+		}
+	})
+
+	t.Run("writer", func(t *testing.T) {
+		b := bytes.Buffer{}
+		w, err := audio.NewWriter(&b, from, to)
+		if err != nil {
+			t.Fatalf("Expected no errors, got %q", err)
+		}
+		for i := 0; i < len(src); i += 333 {
+			end := i + 333
+			if end > len(src) {
+				end = len(src)
+			}
+			if n, err := w.Write(src[i:end]); err != nil || n != end-i {
+				t.Fatalf("Expected to write %d bytes, wrote %d with error %v", end-i, n, err)
+			}
+		}
+		if !bytes.Equal(b.Bytes(), exp) {
+			t.Errorf("Expected streamed conversion to match Convert (%d bytes), got %d bytes", len(exp), b.Len())
+		}
+	})
+}
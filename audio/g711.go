@@ -0,0 +1,145 @@
+// Package audio provides pure-Go utilities to process audio returned by the Elevenlabs API, such as
+// μ-law/A-law (G.711) encoding and decoding, sample-rate conversion and channel conversion of 16-bit
+// linear PCM.
+//
+// Linear PCM audio is represented either as []int16 samples or as signed 16-bit little-endian (S16LE)
+// bytes, which is the layout of the API's pcm_* output formats.
+package audio
+
+const (
+	ulawBias = 0x84
+	ulawClip = 32635
+)
+
+var (
+	ulawTable [256]int16
+	alawTable [256]int16
+)
+
+func init() {
+	for i := 0; i < 256; i++ {
+		ulawTable[i] = decodeULaw(byte(i))
+		alawTable[i] = decodeALaw(byte(i))
+	}
+}
+
+// LinearToULaw encodes a 16-bit linear PCM sample to an 8-bit μ-law sample.
+func LinearToULaw(sample int16) byte {
+	s := int(sample)
+	var sign byte
+	if s < 0 {
+		s = -s
+		sign = 0x80
+	}
+	if s > ulawClip {
+		s = ulawClip
+	}
+	s += ulawBias
+	exponent := 7
+	for mask := 0x4000; s&mask == 0 && exponent > 0; mask >>= 1 {
+		exponent--
+	}
+	mantissa := (s >> (exponent + 3)) & 0x0F
+	return ^(sign | byte(exponent<<4) | byte(mantissa))
+}
+
+// ULawToLinear decodes an 8-bit μ-law sample to a 16-bit linear PCM sample.
+func ULawToLinear(u byte) int16 {
+	return ulawTable[u]
+}
+
+func decodeULaw(u byte) int16 {
+	u = ^u
+	exponent := int(u>>4) & 0x07
+	mantissa := int(u) & 0x0F
+	s := ((mantissa << 3) + ulawBias) << exponent
+	s -= ulawBias
+	if u&0x80 != 0 {
+		return int16(-s)
+	}
+	return int16(s)
+}
+
+// LinearToALaw encodes a 16-bit linear PCM sample to an 8-bit A-law sample.
+func LinearToALaw(sample int16) byte {
+	s := int(sample) >> 3
+	var mask byte = 0xD5
+	if s < 0 {
+		mask = 0x55
+		s = -s - 1
+	}
+	seg := 0
+	for end := 0x1F; seg < 8 && s > end; end = end<<1 | 1 {
+		seg++
+	}
+	if seg >= 8 {
+		return 0x7F ^ mask
+	}
+	a := byte(seg << 4)
+	if seg < 2 {
+		a |= byte(s>>1) & 0x0F
+	} else {
+		a |= byte(s>>seg) & 0x0F
+	}
+	return a ^ mask
+}
+
+// ALawToLinear decodes an 8-bit A-law sample to a 16-bit linear PCM sample.
+func ALawToLinear(a byte) int16 {
+	return alawTable[a]
+}
+
+func decodeALaw(a byte) int16 {
+	a ^= 0x55
+	t := int(a&0x0F) << 4
+	seg := int(a&0x70) >> 4
+	switch seg {
+	case 0:
+		t += 8
+	case 1:
+		t += 0x108
+	default:
+		t += 0x108
+		t <<= seg - 1
+	}
+	if a&0x80 != 0 {
+		return int16(t)
+	}
+	return int16(-t)
+}
+
+// EncodeULaw encodes 16-bit linear PCM samples to μ-law.
+func EncodeULaw(samples []int16) []byte {
+	out := make([]byte, len(samples))
+	for i, s := range samples {
+		out[i] = LinearToULaw(s)
+	}
+	return out
+}
+
+// DecodeULaw decodes μ-law samples to 16-bit linear PCM.
+func DecodeULaw(ulaw []byte) []int16 {
+	out := make([]int16, len(ulaw))
+	for i, u := range ulaw {
+		out[i] = ulawTable[u]
+	}
+	return out
+}
+
+// EncodeALaw encodes 16-bit linear PCM samples to A-law.
+func EncodeALaw(samples []int16) []byte {
+	out := make([]byte, len(samples))
+	for i, s := range samples {
+		out[i] = LinearToALaw(s)
+	}
+	return out
+}
+
+// DecodeALaw decodes A-law samples to 16-bit linear PCM.
+func DecodeALaw(alaw []byte) []int16 {
+	out := make([]int16, len(alaw))
+	for i, a := range alaw {
+		out[i] = alawTable[a]
+	}
+	return out
+}
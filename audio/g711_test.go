@@ -0,0 +1,106 @@
+package audio_test
+
+import (
+	"testing"
+
+	"github.com/haguro/elevenlabs-go/audio"
+)
+
+func TestG711KnownValues(t *testing.T) {
+	testCases := []struct {
+		name string
+		got  int
+		exp  int
+	}{
+		{"ulaw encode 0", int(audio.LinearToULaw(0)), 0xFF},
+		{"ulaw encode max", int(audio.LinearToULaw(32767)), 0x80},
+		{"ulaw encode min", int(audio.LinearToULaw(-32768)), 0x00},
+		{"ulaw decode 0xFF", int(audio.ULawToLinear(0xFF)), 0},
+		{"ulaw decode 0x80", int(audio.ULawToLinear(0x80)), 32124},
+		{"ulaw decode 0x00", int(audio.ULawToLinear(0x00)), -32124},
+		{"alaw encode 0", int(audio.LinearToALaw(0)), 0xD5},
+		{"alaw encode -1", int(audio.LinearToALaw(-1)), 0x55},
+		{"alaw encode max", int(audio.LinearToALaw(32767)), 0xAA},
+		{"alaw decode 0xD5", int(audio.ALawToLinear(0xD5)), 8},
+		{"alaw decode 0x55", int(audio.ALawToLinear(0x55)), -8},
+		{"alaw decode 0xAA", int(audio.ALawToLinear(0xAA)), 32256},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.got != tc.exp {
+				t.Errorf("Expected %#x, got %#x", tc.exp, tc.got)
+			}
+		})
+	}
+}
+
+func TestG711RoundTrip(t *testing.T) {
+	abs := func(x int) int {
+		if x < 0 {
+			return -x
+		}
+		return x
+	}
+	for s := -32768; s <= 32767; s += 7 {
+		sample := int16(s)
+		// The G.711 quantization step grows with the magnitude of the sample and never exceeds 1/16 of it,
+		// plus a small constant for values close to zero.
+		maxErr := abs(s)/16 + 16
+		if got := int(audio.ULawToLinear(audio.LinearToULaw(sample))); abs(got-s) > maxErr+ulawClipErr(s) {
+			t.Fatalf("μ-law round trip of %d returned %d", s, got)
+		}
+		if got := int(audio.ALawToLinear(audio.LinearToALaw(sample))); abs(got-s) > maxErr {
+			t.Fatalf("A-law round trip of %d returned %d", s, got)
+		}
+	}
+
+	for b := 0; b < 256; b++ {
+		u := byte(b)
+		// 0x7F and 0xFF both decode to 0, which encodes to 0xFF.
+		if u == 0x7F {
+			continue
+		}
+		if got := audio.LinearToULaw(audio.ULawToLinear(u)); got != u {
+			t.Errorf("Expected μ-law code %#x to survive a round trip, got %#x", u, got)
+		}
+		if got := audio.LinearToALaw(audio.ALawToLinear(u)); got != u {
+			t.Errorf("Expected A-law code %#x to survive a round trip, got %#x", u, got)
+		}
+	}
+}
+
+// ulawClipErr returns the additional error expected for samples beyond μ-law's clipping level.
+func ulawClipErr(s int) int {
+	const clip = 32635
+	switch {
+	case s > clip:
+		return s - clip
+	case s < -clip:
+		return -clip - s
+	}
+	return 0
+}
+
+func TestG711SliceFunctions(t *testing.T) {
+	samples := []int16{0, 1000, -1000, 32000, -32000}
+	ulaw := audio.EncodeULaw(samples)
+	alaw := audio.EncodeALaw(samples)
+	if len(ulaw) != len(samples) || len(alaw) != len(samples) {
+		t.Fatalf("Expected one encoded byte per sample, got %d and %d", len(ulaw), len(alaw))
+	}
+	for i, s := range samples {
+		if ulaw[i] != audio.LinearToULaw(s) || alaw[i] != audio.LinearToALaw(s) {
+			t.Errorf("Slice encoding of sample %d does not match scalar encoding", i)
+		}
+	}
+	for i, s := range audio.DecodeULaw(ulaw) {
+		if s != audio.ULawToLinear(ulaw[i]) {
+			t.Errorf("Slice μ-law decoding of sample %d does not match scalar decoding", i)
+		}
+	}
+	for i, s := range audio.DecodeALaw(alaw) {
+		if s != audio.ALawToLinear(alaw[i]) {
+			t.Errorf("Slice A-law decoding of sample %d does not match scalar decoding", i)
+		}
+	}
+}
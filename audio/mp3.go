@@ -0,0 +1,380 @@
+package audio
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+var (
+	// ErrNoMP3Frames is returned when no MPEG audio frames could be found in the input.
+	ErrNoMP3Frames = errors.New("no mp3 frames found")
+	// ErrMP3Mismatch is returned when concatenating MP3 audio with different sample rates or MPEG versions.
+	ErrMP3Mismatch = errors.New("mismatched mp3 streams")
+)
+
+// MPEGVersion represents the MPEG audio version of an MP3 frame.
+type MPEGVersion int
+
+const (
+	MPEG1 MPEGVersion = iota + 1
+	MPEG2
+	MPEG25
+)
+
+func (v MPEGVersion) String() string {
+	switch v {
+	case MPEG1:
+		return "MPEG-1"
+	case MPEG2:
+		return "MPEG-2"
+	case MPEG25:
+		return "MPEG-2.5"
+	}
+	return fmt.Sprintf("MPEGVersion(%d)", int(v))
+}
+
+var (
+	mp3Bitrates = map[MPEGVersion][16]int{
+		MPEG1:  {0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, -1},
+		MPEG2:  {0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160, -1},
+		MPEG25: {0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160, -1},
+	}
+	mp3SampleRates = map[MPEGVersion][3]int{
+		MPEG1:  {44100, 48000, 32000},
+		MPEG2:  {22050, 24000, 16000},
+		MPEG25: {11025, 12000, 8000},
+	}
+)
+
+// MP3FrameHeader contains the properties of a single MPEG-1/2/2.5 Layer III frame.
+type MP3FrameHeader struct {
+	Version MPEGVersion
+	// Bitrate is the bitrate of the frame in bits per second.
+	Bitrate int
+	// SampleRate is the sample rate in Hz.
+	SampleRate int
+	Channels   int
+	// Samples is the number of samples (per channel) encoded in the frame.
+	Samples int
+	// Length is the length of the frame in bytes, including the header.
+	Length    int
+	protected bool
+}
+
+// parseMP3FrameHeader parses a 4-byte frame header. Only Layer III frames with a fixed bitrate index are
+// recognised, which covers all the audio produced by the API.
+func parseMP3FrameHeader(b []byte) (MP3FrameHeader, bool) {
+	if len(b) < 4 || b[0] != 0xFF || b[1]&0xE0 != 0xE0 {
+		return MP3FrameHeader{}, false
+	}
+	var h MP3FrameHeader
+	switch (b[1] >> 3) & 0x03 {
+	case 0:
+		h.Version = MPEG25
+	case 2:
+		h.Version = MPEG2
+	case 3:
+		h.Version = MPEG1
+	default:
+		return MP3FrameHeader{}, false
+	}
+	if (b[1]>>1)&0x03 != 1 { // Layer III
+		return MP3FrameHeader{}, false
+	}
+	h.protected = b[1]&0x01 == 0
+	bitrateIdx, srIdx := b[2]>>4, (b[2]>>2)&0x03
+	if bitrateIdx == 0 || bitrateIdx == 15 || srIdx == 3 {
+		return MP3FrameHeader{}, false
+	}
+	h.Bitrate = mp3Bitrates[h.Version][bitrateIdx] * 1000
+	h.SampleRate = mp3SampleRates[h.Version][srIdx]
+	padding := int(b[2]>>1) & 0x01
+	h.Channels = 2
+	if b[3]>>6 == 3 {
+		h.Channels = 1
+	}
+	if h.Version == MPEG1 {
+		h.Samples = 1152
+		h.Length = 144*h.Bitrate/h.SampleRate + padding
+	} else {
+		h.Samples = 576
+		h.Length = 72*h.Bitrate/h.SampleRate + padding
+	}
+	return h, true
+}
+
+// sideInfoSize returns the size of the Layer III side information following the header (and CRC).
+func (h MP3FrameHeader) sideInfoSize() int {
+	if h.Version == MPEG1 {
+		if h.Channels == 1 {
+			return 17
+		}
+		return 32
+	}
+	if h.Channels == 1 {
+		return 9
+	}
+	return 17
+}
+
+// isVBRHeaderFrame reports whether a frame holds a Xing/Info or VBRI header rather than audio.
+func isVBRHeaderFrame(h MP3FrameHeader, frame []byte) bool {
+	off := 4 + h.sideInfoSize()
+	if h.protected {
+		off += 2
+	}
+	if len(frame) >= off+4 {
+		if tag := string(frame[off : off+4]); tag == "Xing" || tag == "Info" {
+			return true
+		}
+	}
+	return len(frame) >= 40 && string(frame[36:40]) == "VBRI"
+}
+
+// id3v2Size returns the total size of an ID3v2 tag starting at b, or 0 if b does not start with one.
+func id3v2Size(b []byte) int {
+	if len(b) < 10 || string(b[:3]) != "ID3" {
+		return 0
+	}
+	for _, c := range b[6:10] { // Sizes are stored as syncsafe integers.
+		if c&0x80 != 0 {
+			return 0
+		}
+	}
+	size := 10 + (int(b[6])<<21 | int(b[7])<<14 | int(b[8])<<7 | int(b[9]))
+	if b[5]&0x10 != 0 { // Footer present
+		size += 10
+	}
+	return size
+}
+
+const id3v1Size = 128
+
+// MP3Scanner reads the audio frames of an MP3 stream one at a time, skipping ID3v1/ID3v2 tags, Xing/Info/VBRI
+// header frames and any data that is not a valid frame.
+//
+// The NewMP3Scanner function should be used when instantiating a new MP3Scanner.
+type MP3Scanner struct {
+	r      *bufio.Reader
+	frame  []byte
+	header MP3FrameHeader
+	err    error
+	first  bool
+}
+
+// NewMP3Scanner creates and returns a new MP3Scanner reading from r.
+func NewMP3Scanner(r io.Reader) *MP3Scanner {
+	return &MP3Scanner{r: bufio.NewReaderSize(r, 8192), first: true}
+}
+
+// Next advances the scanner to the next audio frame, which will then be available through the Frame and
+// Header methods. It returns false when there are no more frames or an error occurred.
+func (s *MP3Scanner) Next() bool {
+	if s.err != nil {
+		return false
+	}
+	resynced := false
+	for {
+		b, err := s.r.Peek(10)
+		if len(b) < 4 {
+			if err != nil && err != io.EOF {
+				s.err = err
+			}
+			return false
+		}
+		if n := id3v2Size(b); n > 0 {
+			if !s.discard(n) {
+				return false
+			}
+			continue
+		}
+		if string(b[:3]) == "TAG" {
+			if tag, _ := s.r.Peek(id3v1Size); len(tag) == id3v1Size {
+				if !s.discard(id3v1Size) {
+					return false
+				}
+				continue
+			}
+		}
+		h, ok := parseMP3FrameHeader(b)
+		if !ok {
+			resynced = true
+			if !s.discard(1) {
+				return false
+			}
+			continue
+		}
+		frame, err := s.r.Peek(h.Length + 4)
+		if len(frame) < h.Length {
+			if err != nil && err != io.EOF {
+				s.err = err
+			}
+			// A truncated frame at the end of the stream is dropped.
+			return false
+		}
+		// When syncing (at the start or after garbage), make sure the next frame header also looks valid to
+		// avoid false syncs on random data that happens to resemble a header.
+		if (resynced || s.first) && len(frame) >= h.Length+4 {
+			next := frame[h.Length:]
+			if _, ok := parseMP3FrameHeader(next); !ok && string(next[:3]) != "TAG" && string(next[:3]) != "ID3" {
+				resynced = true
+				if !s.discard(1) {
+					return false
+				}
+				continue
+			}
+		}
+		s.frame = append(s.frame[:0], frame[:h.Length]...)
+		s.header = h
+		if !s.discard(h.Length) {
+			return false
+		}
+		if s.first {
+			s.first = false
+			if isVBRHeaderFrame(h, s.frame) {
+				continue
+			}
+		}
+		return true
+	}
+}
+
+func (s *MP3Scanner) discard(n int) bool {
+	if _, err := s.r.Discard(n); err != nil {
+		if err != io.EOF {
+			s.err = err
+		}
+		return false
+	}
+	return true
+}
+
+// Frame returns the current frame, including its header. The returned slice is only valid until the next
+// call to Next.
+func (s *MP3Scanner) Frame() []byte {
+	return s.frame
+}
+
+// Header returns the header of the current frame.
+func (s *MP3Scanner) Header() MP3FrameHeader {
+	return s.header
+}
+
+// Err returns the first non-EOF error encountered by the scanner.
+func (s *MP3Scanner) Err() error {
+	return s.err
+}
+
+// MP3Info contains the properties of MP3 audio.
+type MP3Info struct {
+	Duration time.Duration
+	// Bitrate is the average bitrate of the audio frames in bits per second.
+	Bitrate    int
+	SampleRate int
+	Channels   int
+	Frames     int
+	// VBR is true if the frames do not all share the same bitrate.
+	VBR bool
+	// AudioBytes is the total size of the audio frames, excluding tags and header frames.
+	AudioBytes int64
+}
+
+// ScanMP3 reads MP3 audio from r and returns its properties.
+//
+// It returns an error wrapping ErrNoMP3Frames if no audio frames were found.
+func ScanMP3(r io.Reader) (MP3Info, error) {
+	var info MP3Info
+	var samples int64
+	s := NewMP3Scanner(r)
+	for s.Next() {
+		h := s.Header()
+		if info.Frames == 0 {
+			info.SampleRate = h.SampleRate
+			info.Channels = h.Channels
+			info.Bitrate = h.Bitrate
+		} else if h.Bitrate != info.Bitrate {
+			info.VBR = true
+		}
+		info.Frames++
+		info.AudioBytes += int64(h.Length)
+		samples += int64(h.Samples)
+	}
+	if err := s.Err(); err != nil {
+		return MP3Info{}, err
+	}
+	if info.Frames == 0 {
+		return MP3Info{}, ErrNoMP3Frames
+	}
+	info.Duration = time.Duration(samples * int64(time.Second) / int64(info.SampleRate))
+	if seconds := float64(samples) / float64(info.SampleRate); seconds > 0 {
+		info.Bitrate = int(float64(info.AudioBytes*8) / seconds)
+	}
+	return info, nil
+}
+
+// ParseMP3 returns the properties of MP3 audio, such as the audio returned by TextToSpeech.
+//
+// It returns an error wrapping ErrNoMP3Frames if no audio frames were found.
+func ParseMP3(data []byte) (MP3Info, error) {
+	return ScanMP3(bytes.NewReader(data))
+}
+
+// StripMP3 returns only the audio frames of MP3 audio, removing ID3 tags, Xing/Info/VBRI header frames
+// and any invalid data.
+func StripMP3(data []byte) ([]byte, error) {
+	b := bytes.Buffer{}
+	if err := ConcatMP3Stream(&b, bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// ConcatMP3 concatenates several MP3 files into one without re-encoding. Tags and Xing/Info/VBRI header frames
+// of every part are dropped so that players do not stop or miscalculate the duration after the first part.
+//
+// It returns an error wrapping ErrMP3Mismatch if the parts do not share the same MPEG version and sample rate,
+// or ErrNoMP3Frames if a part contains no audio frames.
+func ConcatMP3(parts ...[]byte) ([]byte, error) {
+	readers := make([]io.Reader, len(parts))
+	for i, p := range parts {
+		readers[i] = bytes.NewReader(p)
+	}
+	b := bytes.Buffer{}
+	if err := ConcatMP3Stream(&b, readers...); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// ConcatMP3Stream works like ConcatMP3 but reads the parts from io.Readers and writes the result to w
+// as each frame is read.
+func ConcatMP3Stream(w io.Writer, parts ...io.Reader) error {
+	var first MP3FrameHeader
+	for i, p := range parts {
+		s := NewMP3Scanner(p)
+		frames := 0
+		for s.Next() {
+			h := s.Header()
+			if first.SampleRate == 0 {
+				first = h
+			} else if h.SampleRate != first.SampleRate || h.Version != first.Version {
+				return fmt.Errorf("%w: part %d is %s at %dHz, expected %s at %dHz", ErrMP3Mismatch, i, h.Version, h.SampleRate, first.Version, first.SampleRate)
+			}
+			if _, err := w.Write(s.Frame()); err != nil {
+				return err
+			}
+			frames++
+		}
+		if err := s.Err(); err != nil {
+			return err
+		}
+		if frames == 0 {
+			return fmt.Errorf("%w: part %d", ErrNoMP3Frames, i)
+		}
+	}
+	return nil
+}
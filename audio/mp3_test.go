@@ -0,0 +1,136 @@
+package audio_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/haguro/elevenlabs-go/audio"
+)
+
+// mp3Frame returns a silent MPEG-1 Layer III frame at 44.1kHz with a given bitrate index.
+func mp3Frame(bitrateIdx byte, padding bool) []byte {
+	bitrates := map[byte]int{9: 128000, 11: 192000}
+	length := 144 * bitrates[bitrateIdx] / 44100
+	b2 := bitrateIdx << 4
+	if padding {
+		b2 |= 0x02
+		length++
+	}
+	f := make([]byte, length)
+	copy(f, []byte{0xFF, 0xFB, b2, 0x64})
+	return f
+}
+
+// mpeg2Frame returns a silent mono MPEG-2 Layer III frame at 22.05kHz and 64kbps.
+func mpeg2Frame() []byte {
+	f := make([]byte, 72*64000/22050)
+	copy(f, []byte{0xFF, 0xF3, 0x80, 0xC0})
+	return f
+}
+
+func xingFrame() []byte {
+	f := mp3Frame(9, false)
+	copy(f[36:], "Xing")
+	return f
+}
+
+func id3v2Tag() []byte {
+	tag := []byte{'I', 'D', '3', 3, 0, 0, 0, 0, 1, 1} // Syncsafe size of 129 bytes.
+	return append(tag, bytes.Repeat([]byte{0xFF}, 129)...)
+}
+
+func id3v1Tag() []byte {
+	tag := make([]byte, 128)
+	copy(tag, "TAGtitle")
+	return tag
+}
+
+func join(parts ...[]byte) []byte {
+	return bytes.Join(parts, nil)
+}
+
+func TestParseMP3(t *testing.T) {
+	frames := make([][]byte, 0, 100)
+	for i := 0; i < 100; i++ {
+		frames = append(frames, mp3Frame(9, i%3 == 0))
+	}
+	plain := join(frames...)
+	testCases := []struct {
+		name      string
+		data      []byte
+		expFrames int
+		expVBR    bool
+	}{
+		{"audio frames only", plain, 100, false},
+		{"with tags and Xing header", join(id3v2Tag(), xingFrame(), plain, id3v1Tag()), 100, false},
+		{"with leading garbage", join([]byte{0xFF, 0xFB, 0x00, 0x12, 0xFF}, plain), 100, false},
+		{"variable bitrate", join(plain, mp3Frame(11, false)), 101, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			info, err := audio.ParseMP3(tc.data)
+			if err != nil {
+				t.Fatalf("Expected no errors, got %q", err)
+			}
+			if info.Frames != tc.expFrames {
+				t.Errorf("Expected %d frames, got %d", tc.expFrames, info.Frames)
+			}
+			expDuration := time.Duration(int64(tc.expFrames) * 1152 * int64(time.Second) / 44100)
+			if info.Duration != expDuration {
+				t.Errorf("Expected duration %s, got %s", expDuration, info.Duration)
+			}
+			if info.SampleRate != 44100 || info.Channels != 2 {
+				t.Errorf("Expected 44100Hz stereo, got %dHz with %d channels", info.SampleRate, info.Channels)
+			}
+			if info.VBR != tc.expVBR {
+				t.Errorf("Expected VBR to be %t", tc.expVBR)
+			}
+			if !tc.expVBR && (info.Bitrate < 127500 || info.Bitrate > 128500) {
+				t.Errorf("Expected an average bitrate of about 128kbps, got %d", info.Bitrate)
+			}
+		})
+	}
+
+	if _, err := audio.ParseMP3([]byte("mp3contenthere")); !errors.Is(err, audio.ErrNoMP3Frames) {
+		t.Errorf("Expected error %q, got %v", audio.ErrNoMP3Frames, err)
+	}
+}
+
+func TestStripMP3(t *testing.T) {
+	frames := join(mp3Frame(9, false), mp3Frame(9, true))
+	stripped, err := audio.StripMP3(join(id3v2Tag(), xingFrame(), frames, id3v1Tag()))
+	if err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+	if !bytes.Equal(stripped, frames) {
+		t.Errorf("Expected only the audio frames (%d bytes) to be kept, got %d bytes", len(frames), len(stripped))
+	}
+}
+
+func TestConcatMP3(t *testing.T) {
+	a := join(mp3Frame(9, false), mp3Frame(9, true))
+	b := join(mp3Frame(11, false))
+	out, err := audio.ConcatMP3(join(id3v2Tag(), xingFrame(), a), join(xingFrame(), b, id3v1Tag()))
+	if err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+	if !bytes.Equal(out, join(a, b)) {
+		t.Errorf("Expected concatenation of audio frames (%d bytes), got %d bytes", len(a)+len(b), len(out))
+	}
+	info, err := audio.ParseMP3(out)
+	if err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+	if info.Frames != 3 {
+		t.Errorf("Expected 3 frames in concatenated audio, got %d", info.Frames)
+	}
+
+	if _, err := audio.ConcatMP3(a, mpeg2Frame()); !errors.Is(err, audio.ErrMP3Mismatch) {
+		t.Errorf("Expected error %q, got %v", audio.ErrMP3Mismatch, err)
+	}
+	if _, err := audio.ConcatMP3(a, []byte("not audio")); !errors.Is(err, audio.ErrNoMP3Frames) {
+		t.Errorf("Expected error %q, got %v", audio.ErrNoMP3Frames, err)
+	}
+}
@@ -0,0 +1,178 @@
+package audio
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Encoding represents the way audio samples are encoded.
+type Encoding int
+
+const (
+	// PCM16 is signed 16-bit little-endian linear PCM (S16LE).
+	PCM16 Encoding = iota
+	// ULaw is 8-bit G.711 μ-law.
+	ULaw
+	// ALaw is 8-bit G.711 A-law.
+	ALaw
+)
+
+func (e Encoding) String() string {
+	switch e {
+	case PCM16:
+		return "pcm_s16le"
+	case ULaw:
+		return "ulaw"
+	case ALaw:
+		return "alaw"
+	}
+	return fmt.Sprintf("Encoding(%d)", int(e))
+}
+
+// bytesPerSample returns the size in bytes of a single sample of the encoding.
+func (e Encoding) bytesPerSample() int {
+	if e == PCM16 {
+		return 2
+	}
+	return 1
+}
+
+// ErrUnsupportedFormat is returned when converting from or to a Format that is not supported.
+var ErrUnsupportedFormat = errors.New("unsupported audio format")
+
+// Format describes the layout of a stream of uncompressed audio.
+type Format struct {
+	Encoding Encoding
+	// SampleRate is the sample rate in Hz.
+	SampleRate int
+	// Channels is the number of interleaved channels. Only mono (1) and stereo (2) are supported.
+	// A value of 0 is treated as mono.
+	Channels int
+}
+
+// Common formats returned by the Elevenlabs API.
+var (
+	FormatULaw8000 = Format{Encoding: ULaw, SampleRate: 8000, Channels: 1}
+	FormatALaw8000 = Format{Encoding: ALaw, SampleRate: 8000, Channels: 1}
+	FormatPCM8000  = Format{Encoding: PCM16, SampleRate: 8000, Channels: 1}
+	FormatPCM16000 = Format{Encoding: PCM16, SampleRate: 16000, Channels: 1}
+	FormatPCM22050 = Format{Encoding: PCM16, SampleRate: 22050, Channels: 1}
+	FormatPCM24000 = Format{Encoding: PCM16, SampleRate: 24000, Channels: 1}
+	FormatPCM44100 = Format{Encoding: PCM16, SampleRate: 44100, Channels: 1}
+)
+
+func (f Format) channels() int {
+	if f.Channels == 0 {
+		return 1
+	}
+	return f.Channels
+}
+
+// frameSize returns the size in bytes of one sample for every channel.
+func (f Format) frameSize() int {
+	return f.Encoding.bytesPerSample() * f.channels()
+}
+
+func (f Format) validate() error {
+	if f.Encoding < PCM16 || f.Encoding > ALaw {
+		return fmt.Errorf("%w: encoding %s", ErrUnsupportedFormat, f.Encoding)
+	}
+	if f.SampleRate <= 0 {
+		return fmt.Errorf("%w: sample rate %d", ErrUnsupportedFormat, f.SampleRate)
+	}
+	if c := f.channels(); c != 1 && c != 2 {
+		return fmt.Errorf("%w: %d channels", ErrUnsupportedFormat, c)
+	}
+	return nil
+}
+
+// BytesToSamples converts S16LE bytes to 16-bit samples. A trailing odd byte is ignored.
+func BytesToSamples(pcm []byte) []int16 {
+	out := make([]int16, len(pcm)/2)
+	for i := range out {
+		out[i] = int16(binary.LittleEndian.Uint16(pcm[2*i:]))
+	}
+	return out
+}
+
+// SamplesToBytes converts 16-bit samples to S16LE bytes.
+func SamplesToBytes(samples []int16) []byte {
+	out := make([]byte, 2*len(samples))
+	for i, s := range samples {
+		binary.LittleEndian.PutUint16(out[2*i:], uint16(s))
+	}
+	return out
+}
+
+// StereoToMono downmixes interleaved stereo samples to mono by averaging the left and right channels.
+// A trailing incomplete frame is ignored.
+func StereoToMono(samples []int16) []int16 {
+	out := make([]int16, len(samples)/2)
+	for i := range out {
+		out[i] = int16((int(samples[2*i]) + int(samples[2*i+1])) / 2)
+	}
+	return out
+}
+
+// MonoToStereo duplicates mono samples to both channels of interleaved stereo samples.
+func MonoToStereo(samples []int16) []int16 {
+	out := make([]int16, 2*len(samples))
+	for i, s := range samples {
+		out[2*i] = s
+		out[2*i+1] = s
+	}
+	return out
+}
+
+// Resample converts mono samples from one sample rate to another using linear interpolation.
+//
+// Linear interpolation does not apply any low-pass filtering, which is adequate for the speech rates
+// returned by the API but may introduce some aliasing when downsampling. A copy of the samples is returned
+// unchanged if the rates are equal or either rate is not positive.
+func Resample(samples []int16, fromRate, toRate int) []int16 {
+	if fromRate == toRate || fromRate <= 0 || toRate <= 0 {
+		out := make([]int16, len(samples))
+		copy(out, samples)
+		return out
+	}
+	r := newResampler(fromRate, toRate)
+	return r.process(samples)
+}
+
+// resampler is a stateful linear interpolation resampler that can process a stream of samples in chunks.
+type resampler struct {
+	step    float64 // Input samples advanced per output sample.
+	pos     float64 // Position of the next output sample relative to the first sample of the next buffer.
+	prev    int16
+	hasPrev bool
+}
+
+func newResampler(fromRate, toRate int) *resampler {
+	return &resampler{step: float64(fromRate) / float64(toRate)}
+}
+
+func (r *resampler) process(in []int16) []int16 {
+	if len(in) == 0 {
+		return nil
+	}
+	buf := in
+	if r.hasPrev {
+		buf = make([]int16, 0, len(in)+1)
+		buf = append(buf, r.prev)
+		buf = append(buf, in...)
+	}
+	last := float64(len(buf) - 1)
+	out := make([]int16, 0, int(float64(len(in))/r.step)+1)
+	t := r.pos
+	for ; t < last; t += r.step {
+		i := int(t)
+		frac := t - float64(i)
+		s := float64(buf[i])*(1-frac) + float64(buf[i+1])*frac
+		out = append(out, int16(s))
+	}
+	r.pos = t - last
+	r.prev = buf[len(buf)-1]
+	r.hasPrev = true
+	return out
+}
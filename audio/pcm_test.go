@@ -0,0 +1,60 @@
+package audio_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/haguro/elevenlabs-go/audio"
+)
+
+func TestSamplesBytesConversion(t *testing.T) {
+	samples := []int16{0, 1, -1, 32767, -32768}
+	b := audio.SamplesToBytes(samples)
+	exp := []byte{0, 0, 1, 0, 0xff, 0xff, 0xff, 0x7f, 0, 0x80}
+	if !reflect.DeepEqual(b, exp) {
+		t.Errorf("Expected S16LE bytes %v, got %v", exp, b)
+	}
+	if got := audio.BytesToSamples(append(b, 0x42)); !reflect.DeepEqual(got, samples) {
+		t.Errorf("Expected samples %v, got %v", samples, got)
+	}
+}
+
+func TestChannelConversion(t *testing.T) {
+	stereo := audio.MonoToStereo([]int16{1, -2, 3})
+	if exp := []int16{1, 1, -2, -2, 3, 3}; !reflect.DeepEqual(stereo, exp) {
+		t.Errorf("Expected stereo samples %v, got %v", exp, stereo)
+	}
+	mono := audio.StereoToMono([]int16{10, 20, -10, -30, 7})
+	if exp := []int16{15, -20}; !reflect.DeepEqual(mono, exp) {
+		t.Errorf("Expected mono samples %v, got %v", exp, mono)
+	}
+}
+
+func TestResample(t *testing.T) {
+	testCases := []struct {
+		name     string
+		in       []int16
+		from, to int
+		exp      []int16
+	}{
+		{"same rate", []int16{1, 2, 3}, 16000, 16000, []int16{1, 2, 3}},
+		{"upsample x2", []int16{0, 100, 200}, 8000, 16000, []int16{0, 50, 100, 150}},
+		{"downsample /2", []int16{0, 10, 20, 30, 40, 50}, 16000, 8000, []int16{0, 20, 40}},
+		{"zero source rate", []int16{1, 2, 3}, 0, 8000, []int16{1, 2, 3}},
+		{"zero target rate", []int16{1, 2, 3}, 8000, 0, []int16{1, 2, 3}},
+		{"negative rate", []int16{1, 2, 3}, -8000, 16000, []int16{1, 2, 3}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := audio.Resample(tc.in, tc.from, tc.to)
+			if !reflect.DeepEqual(got, tc.exp) {
+				t.Errorf("Expected %v, got %v", tc.exp, got)
+			}
+		})
+	}
+
+	in := make([]int16, 22050)
+	if got := len(audio.Resample(in, 22050, 16000)); got < 15999 || got > 16000 {
+		t.Errorf("Expected one second of 22.05kHz audio to be resampled to ~16000 samples, got %d", got)
+	}
+}
@@ -0,0 +1,48 @@
+package elevenlabs
+
+import (
+	"sync"
+	"time"
+)
+
+// ttlCache holds the result of an API call for up to ttl, so that lookups do not repeat the call every time.
+type ttlCache[T any] struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	value   T
+	valid   bool
+	fetched time.Time
+}
+
+// get returns the cached value, calling fetch to replace it if it is missing or older than the TTL. Errors are
+// not cached.
+func (tc *ttlCache[T]) get(fetch func() (T, error)) (T, error) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	if tc.valid && tc.ttl > 0 && time.Since(tc.fetched) < tc.ttl {
+		return tc.value, nil
+	}
+	v, err := fetch()
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	tc.value, tc.valid, tc.fetched = v, true, time.Now()
+	return v, nil
+}
+
+func (tc *ttlCache[T]) setTTL(ttl time.Duration) {
+	tc.mu.Lock()
+	tc.ttl = ttl
+	tc.mu.Unlock()
+}
+
+func (tc *ttlCache[T]) invalidate() {
+	if tc == nil {
+		return
+	}
+	tc.mu.Lock()
+	var zero T
+	tc.value, tc.valid = zero, false
+	tc.mu.Unlock()
+}
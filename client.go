@@ -19,6 +19,8 @@ const (
 	elevenlabsBaseURL = "https://api.elevenlabs.io/v1"
 	defaultTimeout    = 30 * time.Second
 	contentTypeJSON   = "application/json"
+	startAfterQuery   = "start_after_history_item_id"
+	shareBaseURL      = "https://elevenlabs.io/share"
 )
 
 var (
@@ -42,6 +44,10 @@ type Client struct {
 	apiKey  string
 	timeout time.Duration
 	ctx     context.Context
+	voices  *ttlCache[[]Voice]
+	models  *ttlCache[[]Model]
+	// subscription caches the subscription used to validate output formats before text to speech requests.
+	subscription *ttlCache[Subscription]
 }
 
 func getDefaultClient() *Client {
@@ -78,15 +84,34 @@ func SetTimeout(timeout time.Duration) {
 //
 // It returns a pointer to a newly created Client.
 func NewClient(ctx context.Context, apiKey string, reqTimeout time.Duration) *Client {
-	return &Client{baseURL: elevenlabsBaseURL, apiKey: apiKey, timeout: reqTimeout, ctx: ctx}
+	return &Client{
+		baseURL: elevenlabsBaseURL,
+		apiKey:  apiKey,
+		timeout: reqTimeout,
+		ctx:     ctx,
+		voices:  &ttlCache[[]Voice]{ttl: DefaultVoiceCacheTTL},
+		models:  &ttlCache[[]Model]{ttl: DefaultModelCacheTTL},
+
+		subscription: &ttlCache[Subscription]{ttl: DefaultSubscriptionCacheTTL},
+	}
 }
 
 func (c *Client) doRequest(ctx context.Context, RespBodyWriter io.Writer, method, url string, bodyBuf io.Reader, contentType string, queries ...QueryFunc) error {
+	_, err := c.doRequestWithHeader(ctx, RespBodyWriter, method, url, bodyBuf, contentType, queries...)
+	return err
+}
+
+// doRequestWithHeader is like doRequest but also returns the headers of a successful response.
+func (c *Client) doRequestWithHeader(ctx context.Context, RespBodyWriter io.Writer, method, url string, bodyBuf io.Reader, contentType string, queries ...QueryFunc) (http.Header, error) {
 	timeoutCtx, cancel := context.WithTimeout(ctx, c.timeout)
 	defer cancel()
 	req, err := http.NewRequestWithContext(timeoutCtx, method, url, bodyBuf)
 	if err != nil {
-		return err
+		// Streamed bodies (see AddEditVoiceRequest) must be closed for their writer to stop.
+		if closer, ok := bodyBuf.(io.Closer); ok {
+			closer.Close()
+		}
+		return nil, err
 	}
 
 	req.Header.Add("Accept", "*/*")
@@ -106,35 +131,35 @@ func (c *Client) doRequest(ctx context.Context, RespBodyWriter io.Writer, method
 	client := &http.Client{}
 	resp, err := client.Do(req)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		respBody, err := io.ReadAll(resp.Body)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		switch resp.StatusCode {
 		case http.StatusBadRequest, http.StatusUnauthorized:
 			apiErr := &APIError{}
 			if err := json.Unmarshal(respBody, apiErr); err != nil {
-				return err
+				return nil, err
 			}
-			return apiErr
+			return nil, apiErr
 		case http.StatusUnprocessableEntity:
 			valErr := &ValidationError{}
 			if err := json.Unmarshal(respBody, valErr); err != nil {
-				return err
+				return nil, err
 			}
-			return valErr
+			return nil, valErr
 		default:
-			return fmt.Errorf("unexpected HTTP status \"%d %s\" returned from server", resp.StatusCode, http.StatusText(resp.StatusCode))
+			return nil, fmt.Errorf("unexpected HTTP status \"%d %s\" returned from server", resp.StatusCode, http.StatusText(resp.StatusCode))
 		}
 	}
 
 	_, err = io.Copy(RespBodyWriter, resp.Body)
-	return err
+	return resp.Header, err
 }
 
 // LatencyOptimizations returns a QueryFunc that sets the http query 'optimize_streaming_latency' to
@@ -157,21 +182,16 @@ func LatencyOptimizations(value int) QueryFunc {
 // It is meant to be used used with TextToSpeech and TextToSpeechStream to change the output format to
 // a value other than the default (mp3_44100_128).
 //
-// Possible values:
-// mp3_22050_32 - mp3 with 22.05kHz sample rate at 32kbps.
-// mp3_44100_32 - mp3 with 44.1kHz sample rate at 32kbps.
-// mp3_44100_64 - mp3 with 44.1kHz sample rate at 64kbps.
-// mp3_44100_96 - mp3 with 44.1kHz sample rate at 96kbps.
-// mp3_44100_128 - mp3 with 44.1kHz sample rate at 128kbps (default)
-// mp3_44100_192 - mp3 with 44.1kHz sample rate at 192kbps (Requires subscription of Creator tier or above).
-// pcm_16000 - PCM (S16LE) with 16kHz sample rate.
-// pcm_22050 - PCM (S16LE) with 22.05kHz sample rate.
-// pcm_24000 - PCM (S16LE) with 24kHz sample rate.
-// pcm_44100 - PCM (S16LE) with 44.1kHz sample rate (Requires subscription of Independent Publisher tier or above).
-// ulaw_8000 - μ-law with 8kHz sample rate. Note that this format is commonly used for Twilio audio inputs.
-func OutputFormat(value string) QueryFunc {
+// The possible values are the AudioFormat constants (e.g. FormatMP3_44100_128, FormatPCM_16000 or
+// FormatULaw_8000, the latter being commonly used for Twilio audio inputs). Some formats, such as
+// FormatMP3_44100_192 and FormatPCM_44100, require a higher subscription tier. TextToSpeech and
+// TextToSpeechStream check such formats against the user's subscription before sending the request, and
+// return an error without sending it if the subscription cannot be retrieved.
+//
+// Untyped string constants can still be passed, but string variables must be converted with AudioFormat.
+func OutputFormat(format AudioFormat) QueryFunc {
 	return func(q *url.Values) {
-		q.Add("output_format", value)
+		q.Add("output_format", string(format))
 	}
 }
 
@@ -195,7 +215,68 @@ func PageSize(n int) QueryFunc {
 // It is meant to be used with GetHistory to specify which history item to start with when retrieving history.
 func StartAfter(id string) QueryFunc {
 	return func(q *url.Values) {
-		q.Add("start_after_history_item_id", id)
+		q.Add(startAfterQuery, id)
+	}
+}
+
+// HistoryVoiceID returns a QueryFunc that sets the http query 'voice_id' to a given voice ID. It is meant to be
+// used with GetHistory and HistoryIterator to only retrieve the history items generated with that voice.
+func HistoryVoiceID(voiceID string) QueryFunc {
+	return func(q *url.Values) {
+		q.Add("voice_id", voiceID)
+	}
+}
+
+// HistoryModelID returns a QueryFunc that sets the http query 'model_id' to a given model ID. It is meant to be
+// used with GetHistory and HistoryIterator to only retrieve the history items generated with that model.
+func HistoryModelID(modelID string) QueryFunc {
+	return func(q *url.Values) {
+		q.Add("model_id", modelID)
+	}
+}
+
+// HistoryAfter returns a QueryFunc that sets the http query 'date_after_unix' to a given time. It is meant to
+// be used with GetHistory and HistoryIterator to only retrieve the history items created at or after that time.
+func HistoryAfter(t time.Time) QueryFunc {
+	return func(q *url.Values) {
+		q.Add("date_after_unix", fmt.Sprint(t.Unix()))
+	}
+}
+
+// HistoryBefore returns a QueryFunc that sets the http query 'date_before_unix' to a given time. It is meant to
+// be used with GetHistory and HistoryIterator to only retrieve the history items created before that time.
+func HistoryBefore(t time.Time) QueryFunc {
+	return func(q *url.Values) {
+		q.Add("date_before_unix", fmt.Sprint(t.Unix()))
+	}
+}
+
+// HistorySource returns a QueryFunc that sets the http query 'source' to a given value. It is meant to be used
+// with GetHistory and HistoryIterator to only retrieve the history items created by a certain product.
+//
+// Possible values are the HistorySource constants, i.e. HistorySourceTTS and HistorySourceSTS.
+func HistorySource(source string) QueryFunc {
+	return func(q *url.Values) {
+		q.Add("source", source)
+	}
+}
+
+// HistorySearch returns a QueryFunc that sets the http query 'search' to a given text. It is meant to be used
+// with GetHistory and HistoryIterator to only retrieve the history items matching that text.
+func HistorySearch(text string) QueryFunc {
+	return func(q *url.Values) {
+		q.Add("search", text)
+	}
+}
+
+// withValues returns a QueryFunc that adds all the given values to the http query.
+func withValues(values url.Values) QueryFunc {
+	return func(q *url.Values) {
+		for k, vs := range values {
+			for _, v := range vs {
+				q.Add(k, v)
+			}
+		}
 	}
 }
 
@@ -208,6 +289,9 @@ func StartAfter(id string) QueryFunc {
 //
 // It returns a byte slice that contains mpeg encoded audio data in case of success, or an error.
 func (c *Client) TextToSpeech(voiceID string, ttsReq TextToSpeechRequest, queries ...QueryFunc) ([]byte, error) {
+	if err := c.checkOutputFormat(queries); err != nil {
+		return nil, err
+	}
 	reqBody, err := json.Marshal(ttsReq)
 	if err != nil {
 		return nil, err
@@ -231,6 +315,9 @@ func (c *Client) TextToSpeech(voiceID string, ttsReq TextToSpeechRequest, querie
 //
 // It returns nil if successful or an error otherwise.
 func (c *Client) TextToSpeechStream(streamWriter io.Writer, voiceID string, ttsReq TextToSpeechRequest, queries ...QueryFunc) error {
+	if err := c.checkOutputFormat(queries); err != nil {
+		return err
+	}
 	reqBody, err := json.Marshal(ttsReq)
 	if err != nil {
 		return err
@@ -341,6 +428,7 @@ func (c *Client) GetVoice(voiceId string, queries ...QueryFunc) (Voice, error) {
 //
 // It returns a nil if successful, or an error.
 func (c *Client) DeleteVoice(voiceId string) error {
+	defer c.voices.invalidate()
 	return c.doRequest(c.ctx, &bytes.Buffer{}, http.MethodDelete, fmt.Sprintf("%s/voices/%s", c.baseURL, voiceId), &bytes.Buffer{}, contentTypeJSON)
 }
 
@@ -365,12 +453,13 @@ func (c *Client) EditVoiceSettings(voiceId string, settings VoiceSettings) error
 //
 // It returns the ID of the newly added voice, or an error.
 func (c *Client) AddVoice(voiceReq AddEditVoiceRequest) (string, error) {
-	reqBodyBuf, contentType, err := voiceReq.buildRequestBody()
+	reqBody, contentType, err := voiceReq.buildRequestBody()
 	if err != nil {
 		return "", err
 	}
+	defer c.voices.invalidate()
 	b := bytes.Buffer{}
-	err = c.doRequest(c.ctx, &b, http.MethodPost, fmt.Sprintf("%s/voices/add", c.baseURL), reqBodyBuf, contentType)
+	err = c.doRequest(c.ctx, &b, http.MethodPost, fmt.Sprintf("%s/voices/add", c.baseURL), reqBody, contentType)
 	if err != nil {
 		return "", err
 	}
@@ -388,11 +477,12 @@ func (c *Client) AddVoice(voiceReq AddEditVoiceRequest) (string, error) {
 //
 // It returns nil if successful or an error otherwise.
 func (c *Client) EditVoice(voiceId string, voiceReq AddEditVoiceRequest) error {
-	reqBodyBuf, contentType, err := voiceReq.buildRequestBody()
+	reqBody, contentType, err := voiceReq.buildRequestBody()
 	if err != nil {
 		return err
 	}
-	return c.doRequest(c.ctx, &bytes.Buffer{}, http.MethodPost, fmt.Sprintf("%s/voices/%s/edit", c.baseURL, voiceId), reqBodyBuf, contentType)
+	defer c.voices.invalidate()
+	return c.doRequest(c.ctx, &bytes.Buffer{}, http.MethodPost, fmt.Sprintf("%s/voices/%s/edit", c.baseURL, voiceId), reqBody, contentType)
 }
 
 // DeleteSample deletes a sample associated with a specific voice.
@@ -426,40 +516,83 @@ func (c *Client) GetSampleAudio(voiceId, sampleId string) ([]byte, error) {
 // NextHistoryPageFunc until all history pages are retrieved in which case nil will be returned in its place.
 //
 // As such, a "while"-style for loop or recursive calls to the returned NextHistoryPageFunc can be employed
-// to retrieve all history in a paginated way if needed.
+// to retrieve all history in a paginated way if needed. HistoryIterator provides a simpler way to walk
+// through all history items.
 type NextHistoryPageFunc func(...QueryFunc) (GetHistoryResponse, NextHistoryPageFunc, error)
 
+func (c *Client) getHistory(ctx context.Context, queries ...QueryFunc) (GetHistoryResponse, error) {
+	var historyResp GetHistoryResponse
+	b := bytes.Buffer{}
+	err := c.doRequest(ctx, &b, http.MethodGet, fmt.Sprintf("%s/history", c.baseURL), &bytes.Buffer{}, contentTypeJSON, queries...)
+	if err != nil {
+		return GetHistoryResponse{}, err
+	}
+
+	if err := json.Unmarshal(b.Bytes(), &historyResp); err != nil {
+		return GetHistoryResponse{}, err
+	}
+	return historyResp, nil
+}
+
 // GetHistory retrieves the history of all created audio and their metadata
 //
 // It accepts an optional list of QueryFunc 'queries' to modify the request. The QueryFunc functions
-// relevant for this function are PageSize and StartAfter.
+// relevant for this function are PageSize, StartAfter and the History* filters (e.g. HistoryVoiceID).
 //
 // It returns a GetHistoryResponse object containing the history data, a function of type NextHistoryPageFunc
 // to retrieve the next page of history, and an error.
 func (c *Client) GetHistory(queries ...QueryFunc) (GetHistoryResponse, NextHistoryPageFunc, error) {
-	var historyResp GetHistoryResponse
-	b := bytes.Buffer{}
-	err := c.doRequest(c.ctx, &b, http.MethodGet, fmt.Sprintf("%s/history", c.baseURL), &bytes.Buffer{}, contentTypeJSON, queries...)
+	historyResp, err := c.getHistory(c.ctx, queries...)
 	if err != nil {
 		return GetHistoryResponse{}, nil, err
 	}
 
-	if err := json.Unmarshal(b.Bytes(), &historyResp); err != nil {
-		return GetHistoryResponse{}, nil, err
-	}
-
 	if !historyResp.HasMore {
 		return historyResp, nil, nil
 	}
 
 	nextPageFunc := func(qf ...QueryFunc) (GetHistoryResponse, NextHistoryPageFunc, error) {
-		//TODO copy to new slice to avoid unexpected issues if query changes after few calls.
-		qf = append(queries, append(qf, StartAfter(historyResp.LastHistoryItemId))...)
-		return c.GetHistory(qf...)
+		// Copy to a new slice so that calling the function more than once never reuses (and overwrites) the
+		// backing array of the original queries.
+		nextQueries := make([]QueryFunc, 0, len(queries)+len(qf)+1)
+		nextQueries = append(nextQueries, queries...)
+		nextQueries = append(nextQueries, qf...)
+		nextQueries = append(nextQueries, StartAfter(historyResp.LastHistoryItemId))
+		return c.GetHistory(nextQueries...)
 	}
 	return historyResp, nextPageFunc, nil
 }
 
+// HistoryIterator returns an Iterator that walks through all history items, retrieving history pages as
+// they are needed.
+//
+// It accepts an optional list of QueryFunc 'queries' that are applied to every page request. The QueryFunc
+// functions relevant for this function are PageSize, the History* filters (e.g. HistoryVoiceID) and StartAfter,
+// the latter being used to resume iteration from a cursor previously returned by the iterator's Cursor method.
+//
+// It returns a pointer to an Iterator of HistoryItem.
+func (c *Client) HistoryIterator(queries ...QueryFunc) *Iterator[HistoryItem] {
+	q := url.Values{}
+	for _, qf := range queries {
+		qf(&q)
+	}
+	start := q.Get(startAfterQuery)
+	q.Del(startAfterQuery)
+
+	fetch := func(ctx context.Context, cursor string) (Page[HistoryItem], error) {
+		pageQueries := []QueryFunc{withValues(q)}
+		if cursor != "" {
+			pageQueries = append(pageQueries, StartAfter(cursor))
+		}
+		resp, err := c.getHistory(ctx, pageQueries...)
+		if err != nil {
+			return Page[HistoryItem]{}, err
+		}
+		return Page[HistoryItem]{Items: resp.History, NextCursor: resp.LastHistoryItemId, HasMore: resp.HasMore}, nil
+	}
+	return NewIterator(fetch, start, func(h HistoryItem) string { return h.HistoryItemId })
+}
+
 // GetHistoryItem retrieves a specific history item by its ID.
 //
 // It takes a string argument 'representing the ID of the history item to be retrieved.
@@ -510,7 +643,8 @@ func (c *Client) GetHistoryItemAudio(itemId string) ([]byte, error) {
 //
 // It returns a byte slice containing the downloaded audio data. If one history item ID was provided
 // the byte slice is a mpeg encoded audio file. If multiple item IDs where provided, the byte slice
-// is a zip file packing the history items' audio files.
+// is a zip file packing the history items' audio files. DownloadHistoryAudioFiles can be used instead to
+// access the audio of each history item regardless of the number of items.
 func (c *Client) DownloadHistoryAudio(dlReq DownloadHistoryRequest) ([]byte, error) {
 	reqBody, err := json.Marshal(dlReq)
 	if err != nil {
@@ -525,6 +659,65 @@ func (c *Client) DownloadHistoryAudio(dlReq DownloadHistoryRequest) ([]byte, err
 	return b.Bytes(), nil
 }
 
+// SubmitHistoryFeedback submits feedback on the audio generated for a specific history item.
+//
+// It takes a string argument representing the ID of the history item and a HistoryFeedbackRequest argument
+// that contains the feedback. Its ThumbsUp field is the overall rating, while the other boolean fields flag
+// the specific issues found with the audio.
+//
+// It returns nil if successful or an error otherwise.
+func (c *Client) SubmitHistoryFeedback(itemId string, feedback HistoryFeedbackRequest) error {
+	reqBody, err := json.Marshal(feedback)
+	if err != nil {
+		return err
+	}
+	return c.doRequest(c.ctx, &bytes.Buffer{}, http.MethodPost, fmt.Sprintf("%s/history-item/%s/feedback", c.baseURL, itemId), bytes.NewBuffer(reqBody), contentTypeJSON)
+}
+
+// CreateHistoryShareLink creates a share link for a specific history item so that its audio can be played
+// by anyone with the link, without an API key. If the history item already has a share link, it is returned.
+//
+// It takes a string argument representing the ID of the history item.
+//
+// It returns the ID of the share link, which can be passed to HistoryShareURL to build its public URL, or an
+// error.
+//
+// The share link endpoints are not part of the documented API and may change without notice.
+func (c *Client) CreateHistoryShareLink(itemId string) (string, error) {
+	b := bytes.Buffer{}
+	err := c.doRequest(c.ctx, &b, http.MethodPost, fmt.Sprintf("%s/history/%s/share-link", c.baseURL, itemId), &bytes.Buffer{}, contentTypeJSON)
+	if err != nil {
+		return "", err
+	}
+
+	var shareResp HistoryShareLinkResponse
+	if err := json.Unmarshal(b.Bytes(), &shareResp); err != nil {
+		return "", err
+	}
+	return shareResp.ShareLinkId, nil
+}
+
+// DeleteHistoryShareLink revokes the share link of a specific history item. The public URL of the link stops
+// working immediately.
+//
+// It takes a string argument representing the ID of the history item.
+//
+// It returns nil if successful or an error otherwise.
+//
+// The share link endpoints are not part of the documented API and may change without notice.
+func (c *Client) DeleteHistoryShareLink(itemId string) error {
+	return c.doRequest(c.ctx, &bytes.Buffer{}, http.MethodDelete, fmt.Sprintf("%s/history/%s/share-link", c.baseURL, itemId), &bytes.Buffer{}, contentTypeJSON)
+}
+
+// HistoryShareURL returns the public URL of a given share link ID, as returned by CreateHistoryShareLink or
+// found in HistoryItem.ShareLinkId. It returns an empty string if the ID is empty.
+func HistoryShareURL(shareLinkId string) string {
+	if shareLinkId == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s", shareBaseURL, url.PathEscape(shareLinkId))
+}
+
 // GetSubscription retrieves the subscription details for the user.
 //
 // It returns a Subscription object representing the subscription details, or an error.
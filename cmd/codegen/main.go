@@ -12,6 +12,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 	"text/template"
 )
@@ -25,9 +26,13 @@ const (
 // Run 'go generate' after adding new methods with a '{{.ReceiverType}}' pointer receiver.
 
 package elevenlabs
-
-import "io"
-{{range .Functions}}
+{{if .Imports}}
+import (
+{{- range .Imports}}
+	{{.}}
+{{- end}}
+)
+{{end}}{{range .Functions}}
 // {{.FuncIdent}} calls the {{.FuncIdent}} method on the default client.
 func {{.FuncIdent}}{{.FuncParams}}{{.FuncResults}} {
 	{{if .FuncResults}}return {{end}}{{.MethodReceiver}}.{{.FuncIdent}}{{.FuncArgs}}
@@ -40,6 +45,7 @@ var sourceFiles []string = []string{"client.go", "models.go", "errors.go"}
 type proxyFuncFile struct {
 	GeneratorPath string
 	ReceiverType  string
+	Imports       []string
 	Functions     []proxyFunc
 }
 
@@ -73,9 +79,18 @@ func generate(w io.Writer, pkgFiles map[string]*ast.File) (int, error) {
 		GeneratorPath: g,
 		ReceiverType:  receiverType,
 	}
-	for _, pf := range pkgFiles {
-		methods := ptrRcvMethods(pf, receiverType)
+	fileNames := make([]string, 0, len(pkgFiles))
+	for name := range pkgFiles {
+		fileNames = append(fileNames, name)
+	}
+	sort.Strings(fileNames) // Keep the generated file stable across runs.
+	imports := map[string]bool{}
+	for _, name := range fileNames {
+		methods := ptrRcvMethods(pkgFiles[name], receiverType)
 		for _, m := range methods {
+			for _, spec := range usedImports(pkgFiles[name], m.Type) {
+				imports[spec] = true
+			}
 			sFile.Functions = append(sFile.Functions, proxyFunc{
 				FuncIdent:      m.Name.Name,
 				FuncParams:     genTypedParams(m.Type.Params),
@@ -86,6 +101,10 @@ func generate(w io.Writer, pkgFiles map[string]*ast.File) (int, error) {
 		}
 		total += len(methods)
 	}
+	for spec := range imports {
+		sFile.Imports = append(sFile.Imports, spec)
+	}
+	sort.Strings(sFile.Imports)
 	t := template.Must(template.New("").Parse(genFileTemplate))
 	if err := t.Execute(w, sFile); err != nil {
 		return 0, err
@@ -129,6 +148,35 @@ func ptrRcvMethods(f *ast.File, receiverTypeStr string) []*ast.FuncDecl {
 	return methodDecls
 }
 
+// usedImports returns the import specs of the packages referenced by a method signature, as imported by the
+// file the method is declared in.
+func usedImports(f *ast.File, sig *ast.FuncType) []string {
+	specs := map[string]string{}
+	for _, imp := range f.Imports {
+		path := strings.Trim(imp.Path.Value, "\"")
+		name := path[strings.LastIndex(path, "/")+1:]
+		spec := imp.Path.Value
+		if imp.Name != nil {
+			name = imp.Name.Name
+			spec = name + " " + spec
+		}
+		specs[name] = spec
+	}
+	var used []string
+	ast.Inspect(sig, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if id, ok := sel.X.(*ast.Ident); ok {
+				if spec, ok := specs[id.Name]; ok {
+					used = append(used, spec)
+				}
+			}
+			return false
+		}
+		return true
+	})
+	return used
+}
+
 func genTypedParams(fl *ast.FieldList) string {
 	if fl.List == nil {
 		return "()"
@@ -195,6 +243,14 @@ func exprToString(expr ast.Expr) string {
 		return fmt.Sprintf("func%s%s", genTypedParams(fieldType.Params), genFuncReturnTypes(fieldType.Results))
 	case *ast.SelectorExpr:
 		return fmt.Sprintf("%s.%s", fieldType.X, fieldType.Sel)
+	case *ast.IndexExpr:
+		return fmt.Sprintf("%s[%s]", exprToString(fieldType.X), exprToString(fieldType.Index))
+	case *ast.IndexListExpr:
+		indices := make([]string, len(fieldType.Indices))
+		for i, idx := range fieldType.Indices {
+			indices[i] = exprToString(idx)
+		}
+		return fmt.Sprintf("%s[%s]", exprToString(fieldType.X), strings.Join(indices, ", "))
 	}
 	return fmt.Sprintf("%s", expr)
 }
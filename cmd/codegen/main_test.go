@@ -136,6 +136,13 @@ func TestGenFuncFromMethod(t *testing.T) {
 			expArgsStr:   "(w, f)",
 			expResStr:    " (io.Reader, http.ResponseWriter)",
 		},
+		{
+			name:         "10. Generic types",
+			inSrc:        `func (b *Client) SampleMethod(p Pair[string, *Foo]) (*Iterator[Item], []Page[pkg.Item]) {}`,
+			expParamsStr: "(p Pair[string, *Foo])",
+			expArgsStr:   "(p)",
+			expResStr:    " (*Iterator[Item], []Page[pkg.Item])",
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -165,3 +172,31 @@ func TestGenFuncFromMethod(t *testing.T) {
 		})
 	}
 }
+
+func TestGeneratedImports(t *testing.T) {
+	testSrc := `
+import (
+	"io"
+	"net/http"
+	tm "time"
+)
+
+func (c *Client) First(w io.Writer, d tm.Duration) error {}
+
+func (c *Client) Second(f func(*http.Request) io.Reader) {}
+
+func (c *Client) Third(x int) {}
+`
+	f, err := parser.ParseFile(token.NewFileSet(), "testSrc", packageDef+testSrc, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := bytes.Buffer{}
+	if _, err := generate(&b, map[string]*ast.File{"testSrc": f}); err != nil {
+		t.Fatal(err)
+	}
+	expImports := "import (\n\t\"io\"\n\t\"net/http\"\n\ttm \"time\"\n)\n"
+	if !strings.Contains(b.String(), expImports) {
+		t.Errorf("Expected the generated file to contain:\n%s\nGot:\n%s", expImports, b.String())
+	}
+}
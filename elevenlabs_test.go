@@ -5,11 +5,17 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"io"
+	"io/fs"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
+	"testing/fstest"
+	"testing/iotest"
 	"time"
 
 	"github.com/haguro/elevenlabs-go"
@@ -31,6 +37,43 @@ type testServerConfig struct {
 	statusCode          int
 	responseBody        []byte
 	responseDelay       time.Duration
+	// routes maps "METHOD /path" or "/path" keys to the handlers of the requests to those routes. When set,
+	// expectedMethod, statusCode and responseBody are not used and requests to other routes are reported.
+	routes map[string]http.HandlerFunc
+	// log, if set, records the method and path of every request.
+	log *requestLog
+}
+
+// requestLog records the requests received by a test server as "METHOD /path" strings.
+type requestLog struct {
+	mu       sync.Mutex
+	requests []string
+}
+
+func (l *requestLog) add(r *http.Request) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.requests = append(l.requests, r.Method+" "+r.URL.Path)
+}
+
+// count returns the number of requests received for a "METHOD /path" route.
+func (l *requestLog) count(route string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	n := 0
+	for _, r := range l.requests {
+		if r == route {
+			n++
+		}
+	}
+	return n
+}
+
+// list returns the requests received so far.
+func (l *requestLog) list() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.requests...)
 }
 
 func testServer(t *testing.T, config testServerConfig) *httptest.Server {
@@ -43,6 +86,24 @@ func testServer(t *testing.T, config testServerConfig) *httptest.Server {
 			}
 		}
 
+		if config.log != nil {
+			config.log.add(r)
+		}
+
+		if config.routes != nil {
+			handler := config.routes[r.Method+" "+r.URL.Path]
+			if handler == nil {
+				handler = config.routes[r.URL.Path]
+			}
+			if handler == nil {
+				t.Errorf("Server: unexpected request %s %s", r.Method, r.URL.Path)
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			handler(w, r)
+			return
+		}
+
 		if r.Method != config.expectedMethod {
 			t.Errorf("Server: expected HTTP Method to be %q, got %q", config.expectedMethod, r.Method)
 		}
@@ -211,6 +272,47 @@ func TestErrorOnUnexpectedStatusCode(t *testing.T) {
 	}
 }
 
+func TestTextToSpeechRequestJSON(t *testing.T) {
+	testCases := []struct {
+		name    string
+		req     elevenlabs.TextToSpeechRequest
+		expJSON string
+	}{
+		{
+			name:    "Unset options are omitted",
+			req:     elevenlabs.TextToSpeechRequest{Text: "Test text"},
+			expJSON: `{"text":"Test text"}`,
+		},
+		{
+			name: "All options",
+			req: elevenlabs.TextToSpeechRequest{
+				Text:                   "Test text",
+				LanguageCode:           "de",
+				Seed:                   elevenlabs.Uint32(0),
+				PreviousText:           "Before.",
+				NextText:               "After.",
+				PreviousRequestIds:     []string{"r1"},
+				NextRequestIds:         []string{"r2"},
+				ApplyTextNormalization: elevenlabs.TextNormalizationOn,
+				UsePVCAsIVC:            true,
+			},
+			expJSON: `{"text":"Test text","language_code":"de","seed":0,"previous_text":"Before.","next_text":"After.",` +
+				`"previous_request_ids":["r1"],"next_request_ids":["r2"],"apply_text_normalization":"on","use_pvc_as_ivc":true}`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := json.Marshal(tc.req)
+			if err != nil {
+				t.Fatalf("Expected no errors, got error: %q", err)
+			}
+			if string(b) != tc.expJSON {
+				t.Errorf("Expected JSON %s, got %s", tc.expJSON, b)
+			}
+		})
+	}
+}
+
 func TestTextToSpeech(t *testing.T) {
 	testCases := []struct {
 		name               string
@@ -258,7 +360,7 @@ func TestTextToSpeech(t *testing.T) {
 		{
 			name:           "With API key and latency optimizations and output format queries",
 			excludeAPIKey:  false,
-			queries:        []elevenlabs.QueryFunc{elevenlabs.LatencyOptimizations(3), elevenlabs.OutputFormat("mp3_44100_32")},
+			queries:        []elevenlabs.QueryFunc{elevenlabs.LatencyOptimizations(3), elevenlabs.OutputFormat(elevenlabs.FormatMP3_44100_32)},
 			expQueryString: "optimize_streaming_latency=3&output_format=mp3_44100_32",
 			testRequestBody: elevenlabs.TextToSpeechRequest{
 				ModelID: "model1",
@@ -528,7 +630,7 @@ func TestEditVoiceSettings(t *testing.T) {
 	})
 	defer server.Close()
 	client := elevenlabs.NewMockClient(context.Background(), server.URL, mockAPIKey, mockTimeout)
-	err := client.EditVoiceSettings("TestVoiceID", elevenlabs.VoiceSettings{Stability: 0.2, SimilarityBoost: 0.7, Style: 0.3, SpeakerBoost: false})
+	err := client.EditVoiceSettings("TestVoiceID", elevenlabs.VoiceSettings{Stability: 0.2, SimilarityBoost: 0.7, Style: elevenlabs.Float32(0.3), SpeakerBoost: elevenlabs.Bool(false)})
 	if err != nil {
 		t.Errorf("Expected no errors, got error: %q", err)
 	}
@@ -591,6 +693,67 @@ func TestAddVoice(t *testing.T) {
 	}
 }
 
+func TestAddVoiceFromReaders(t *testing.T) {
+	type part struct {
+		Filename, ContentType, Data string
+	}
+	var gotParts []part
+	var gotFields url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			// Expected when the client aborts the body of a request.
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		gotFields = url.Values(r.MultipartForm.Value)
+		for _, fh := range r.MultipartForm.File["files"] {
+			f, _ := fh.Open()
+			b, _ := io.ReadAll(f)
+			f.Close()
+			gotParts = append(gotParts, part{fh.Filename, fh.Header.Get("Content-Type"), string(b)})
+		}
+		w.Write([]byte(`{"voice_id":"TestVoiceId"}`))
+	}))
+	defer server.Close()
+	client := elevenlabs.NewMockClient(context.Background(), server.URL, mockAPIKey, mockTimeout)
+
+	fsys := fstest.MapFS{"samples/a.wav": {Data: []byte("wav-data")}}
+	id, err := client.AddVoice(elevenlabs.AddEditVoiceRequest{
+		Name:      "NewTestVoiceName",
+		FilePaths: []string{"samples/a.wav"},
+		FS:        fsys,
+		Files: []elevenlabs.VoiceFile{
+			{Name: "b.mp3", Reader: strings.NewReader("mp3-data")},
+			{Name: `c"d.bin`, MimeType: "audio/flac", Reader: bytes.NewReader([]byte("flac-data"))},
+		},
+		Labels: map[string]string{"accent": "australian"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no errors, got error: %q", err)
+	}
+	if id != "TestVoiceId" {
+		t.Errorf("Expected AddVoice to return voice ID %q, got %q", "TestVoiceId", id)
+	}
+	expParts := []part{{"a.wav", "audio/wav", "wav-data"}, {"b.mp3", "audio/mpeg", "mp3-data"}, {`c"d.bin`, "audio/flac", "flac-data"}}
+	if !reflect.DeepEqual(gotParts, expParts) {
+		t.Errorf("Expected file parts %+v, got %+v", expParts, gotParts)
+	}
+	if gotFields.Get("name") != "NewTestVoiceName" || gotFields.Get("labels") != `{"accent":"australian"}` {
+		t.Errorf("Unexpected form fields %v", gotFields)
+	}
+
+	_, err = client.AddVoice(elevenlabs.AddEditVoiceRequest{Name: "Voice", FilePaths: []string{"missing.mp3"}, FS: fsys})
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected error %q, got %v", fs.ErrNotExist, err)
+	}
+
+	readErr := errors.New("read failed")
+	_, err = client.AddVoice(elevenlabs.AddEditVoiceRequest{Name: "Voice", Files: []elevenlabs.VoiceFile{{Name: "a.mp3", Reader: iotest.ErrReader(readErr)}}})
+	if !errors.Is(err, readErr) {
+		t.Errorf("Expected error %q, got %v", readErr, err)
+	}
+}
+
 func TestEditVoice(t *testing.T) {
 	server := testServer(t, testServerConfig{
 		expectedMethod:      http.MethodPost,
@@ -806,6 +969,64 @@ func TestDownloadHistoryAudio(t *testing.T) {
 	}
 }
 
+func TestSubmitHistoryFeedback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("Server: expected method %q, got %q", http.MethodPost, r.Method)
+		}
+		if r.URL.Path != "/history-item/TestHistoryItemID/feedback" {
+			t.Errorf("Server: unexpected path %q", r.URL.Path)
+		}
+		body, _ := io.ReadAll(r.Body)
+		expBody := `{"thumbs_up":false,"feedback":"Robotic ending","emotions":true,"inaccurate_clone":false,"glitches":true,"audio_quality":false,"other":false}`
+		if string(body) != expBody {
+			t.Errorf("Server: expected body %s, got %s", expBody, body)
+		}
+	}))
+	defer server.Close()
+	client := elevenlabs.NewMockClient(context.Background(), server.URL, mockAPIKey, mockTimeout)
+	err := client.SubmitHistoryFeedback("TestHistoryItemID", elevenlabs.HistoryFeedbackRequest{Feedback: "Robotic ending", Emotions: true, Glitches: true})
+	if err != nil {
+		t.Errorf("Expected no errors from `SubmitHistoryFeedback`, got \"%T\" error: %q", err, err)
+	}
+}
+
+func TestHistoryShareLinks(t *testing.T) {
+	var gotMethods []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/history/TestHistoryItemID/share-link" {
+			t.Errorf("Server: unexpected path %q", r.URL.Path)
+		}
+		gotMethods = append(gotMethods, r.Method)
+		if r.Method == http.MethodPost {
+			w.Write([]byte(`{"share_link_id":"TestShareLinkID"}`))
+		}
+	}))
+	defer server.Close()
+	client := elevenlabs.NewMockClient(context.Background(), server.URL, mockAPIKey, mockTimeout)
+
+	id, err := client.CreateHistoryShareLink("TestHistoryItemID")
+	if err != nil {
+		t.Fatalf("Expected no errors from `CreateHistoryShareLink`, got \"%T\" error: %q", err, err)
+	}
+	if id != "TestShareLinkID" {
+		t.Errorf("Expected share link ID %q, got %q", "TestShareLinkID", id)
+	}
+	if err := client.DeleteHistoryShareLink("TestHistoryItemID"); err != nil {
+		t.Errorf("Expected no errors from `DeleteHistoryShareLink`, got \"%T\" error: %q", err, err)
+	}
+	if exp := []string{http.MethodPost, http.MethodDelete}; !reflect.DeepEqual(gotMethods, exp) {
+		t.Errorf("Expected requests with methods %v, got %v", exp, gotMethods)
+	}
+
+	if got, exp := elevenlabs.HistoryShareURL("abc/1"), "https://elevenlabs.io/share/abc%2F1"; got != exp {
+		t.Errorf("Expected share URL %q, got %q", exp, got)
+	}
+	if got := elevenlabs.HistoryShareURL(""); got != "" {
+		t.Errorf("Expected an empty share URL for an empty ID, got %q", got)
+	}
+}
+
 func TestGetSubscription(t *testing.T) {
 	respBody := testRespBodies["TestGetSubscription"]
 	server := testServer(t, testServerConfig{
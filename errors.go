@@ -1,6 +1,7 @@
 package elevenlabs
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 )
@@ -47,3 +48,65 @@ func (e *ValidationError) Error() string {
 	}
 	return "validation error"
 }
+
+// VoiceProblem describes a single problem found when validating a voice request.
+type VoiceProblem struct {
+	// Field is the part of the request the problem relates to: "name", "files", "labels" or "subscription".
+	Field string
+	// Sample is the path or name of the sample the problem relates to, if any.
+	Sample  string
+	Message string
+}
+
+func (p VoiceProblem) String() string {
+	if p.Sample != "" {
+		return fmt.Sprintf("%s %q: %s", p.Field, p.Sample, p.Message)
+	}
+	return fmt.Sprintf("%s: %s", p.Field, p.Message)
+}
+
+// VoiceValidationError is returned when a voice request fails local validation. It lists all the problems found.
+type VoiceValidationError struct {
+	Problems []VoiceProblem
+}
+
+func (e *VoiceValidationError) Error() string {
+	msgs := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		msgs[i] = p.String()
+	}
+	return fmt.Sprintf("voice validation failed - %s", strings.Join(msgs, "; "))
+}
+
+// TextToSpeechValidationError is returned by ValidateTextToSpeech when a text to speech request is not supported
+// by its model or voice.
+type TextToSpeechValidationError struct {
+	Problems []string
+}
+
+func (e *TextToSpeechValidationError) Error() string {
+	return fmt.Sprintf("text to speech validation failed - %s", strings.Join(e.Problems, "; "))
+}
+
+var (
+	// ErrUnknownAudioFormat is returned when an AudioFormat that is not known to this library is validated.
+	ErrUnknownAudioFormat = errors.New("unknown audio format")
+	// ErrAudioFormatTier is returned when an AudioFormat is not available for the user's subscription tier.
+	ErrAudioFormatTier = errors.New("audio format not available for subscription tier")
+	// ErrUnsupportedWAVFormat is returned when audio of a format that cannot be stored in a WAV container is wrapped.
+	ErrUnsupportedWAVFormat = errors.New("audio format cannot be wrapped in a WAV container")
+	// ErrWAVWriterClosed is returned when writing to a closed WAVWriter.
+	ErrWAVWriterClosed = errors.New("write to closed WAVWriter")
+	// ErrPartialFailure is returned when an operation on several items failed for some of them.
+	ErrPartialFailure = errors.New("operation partially failed")
+	// ErrVoiceNotFound is returned when no voice matches a name or query.
+	ErrVoiceNotFound = errors.New("voice not found")
+	// ErrAmbiguousVoice is returned when a voice name matches more than one voice.
+	ErrAmbiguousVoice = errors.New("voice name is ambiguous")
+	// ErrModelNotFound is returned when a model ID does not match any of the available models.
+	ErrModelNotFound = errors.New("model not found")
+	// ErrInvalidVoiceSettings is returned when voice settings are outside of their valid range.
+	ErrInvalidVoiceSettings = errors.New("invalid voice settings")
+	// ErrTextMismatch is returned when the text passed to Replay is not the text of the recorded request.
+	ErrTextMismatch = errors.New("text does not match the recorded hash")
+)
@@ -0,0 +1,236 @@
+package elevenlabs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const (
+	// DefaultExportBatchSize is the default number of history items downloaded at once by
+	// ExportHistory.
+	DefaultExportBatchSize = 10
+	// DefaultExportConcurrency is the default number of batches downloaded concurrently by ExportHistory.
+	DefaultExportConcurrency = 2
+)
+
+// ExportOptions represents the options of a history export.
+type ExportOptions struct {
+	// Dir is the directory the history items are written to. It is created if it does not exist.
+	Dir string
+	// Filter restricts the exported history items. The zero value exports all history items.
+	Filter HistoryFilter
+	// Queries are applied to every history page request, e.g. PageSize or HistorySource.
+	Queries []QueryFunc
+	// BatchSize is the number of history items downloaded at once. Defaults to DefaultExportBatchSize.
+	BatchSize int
+	// Concurrency is the number of batches downloaded concurrently. Defaults to DefaultExportConcurrency.
+	Concurrency int
+	// OnItem, if set, is called after each history item is exported, skipped or failed. The error is nil unless
+	// the item failed. It may be called concurrently.
+	OnItem func(item HistoryItem, skipped bool, err error)
+}
+
+// ExportReport contains the outcome of a history export.
+type ExportReport struct {
+	// Exported contains the IDs of the history items written by the export.
+	Exported []string
+	// Skipped contains the IDs of the history items that had already been exported.
+	Skipped []string
+	// Failed maps the IDs of the history items that could not be exported to the cause.
+	Failed map[string]error
+}
+
+// ExportHistory archives generated audio to a local directory. It walks through all history items matching
+// opts.Filter, downloads their audio in batches with DownloadHistoryAudioStream and writes each item as
+// <history_item_id>.<ext> alongside a <history_item_id>.json file holding the HistoryItem metadata.
+//
+// The JSON file of an item is written last, and items with an existing JSON file are skipped, so an
+// interrupted export can simply be run again to resume it, and periodic exports only download new items.
+//
+// It takes an ExportOptions argument that specifies the target directory and how the export is performed.
+//
+// It returns an ExportReport and an error if the history could not be retrieved or the directory could not
+// be created. Failures to download or write individual items are recorded in the report instead.
+func (c *Client) ExportHistory(opts ExportOptions) (ExportReport, error) {
+	report := ExportReport{Failed: map[string]error{}}
+	if err := os.MkdirAll(opts.Dir, 0o755); err != nil {
+		return report, err
+	}
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultExportBatchSize
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultExportConcurrency
+	}
+
+	var mu sync.Mutex
+	record := func(item HistoryItem, skipped bool, err error) {
+		mu.Lock()
+		switch {
+		case err != nil:
+			report.Failed[item.HistoryItemId] = err
+		case skipped:
+			report.Skipped = append(report.Skipped, item.HistoryItemId)
+		default:
+			report.Exported = append(report.Exported, item.HistoryItemId)
+		}
+		mu.Unlock()
+		if opts.OnItem != nil {
+			opts.OnItem(item, skipped, err)
+		}
+	}
+
+	batches := make(chan []HistoryItem)
+	wg := sync.WaitGroup{}
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range batches {
+				c.exportBatch(opts.Dir, batch, record)
+			}
+		}()
+	}
+
+	it := c.HistoryIterator(append(opts.Filter.Queries(), opts.Queries...)...)
+	var batch []HistoryItem
+	for it.Next(c.ctx) {
+		item := it.Item()
+		if !opts.Filter.Match(item) {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(opts.Dir, item.HistoryItemId+".json")); err == nil {
+			record(item, true, nil)
+			continue
+		}
+		batch = append(batch, item)
+		if len(batch) == batchSize {
+			batches <- batch
+			batch = nil
+		}
+	}
+	if len(batch) > 0 {
+		batches <- batch
+	}
+	close(batches)
+	wg.Wait()
+	return report, it.Err()
+}
+
+// exportBatch downloads and writes the audio and metadata of a batch of history items. The downloaded data is
+// streamed to a temporary file in dir rather than buffered in memory. Items whose audio cannot be matched by name
+// in the downloaded zip file are downloaded again individually rather than guessed from the order of the entries.
+func (c *Client) exportBatch(dir string, batch []HistoryItem, record func(HistoryItem, bool, error)) {
+	ids := make([]string, len(batch))
+	for i, item := range batch {
+		ids[i] = item.HistoryItemId
+	}
+	fail := func(err error) {
+		for _, item := range batch {
+			record(item, false, err)
+		}
+	}
+
+	tmp, err := os.CreateTemp(dir, "download-*.tmp")
+	if err != nil {
+		fail(err)
+		return
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+	if err := c.DownloadHistoryAudioStream(tmp, DownloadHistoryRequest{HistoryItemIds: ids}); err != nil {
+		fail(err)
+		return
+	}
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		fail(err)
+		return
+	}
+	audio, err := OpenHistoryAudio(tmp, size, ids)
+	if err != nil {
+		fail(err)
+		return
+	}
+	for _, item := range batch {
+		entry, ok := audio.Entry(item.HistoryItemId)
+		if !ok && len(batch) > 1 {
+			// The item could not be matched by name, so it is downloaded on its own as a single item is
+			// returned as a single file.
+			c.exportBatch(dir, []HistoryItem{item}, record)
+			continue
+		}
+		if !ok {
+			record(item, false, fmt.Errorf("history item %s missing from download", item.HistoryItemId))
+			continue
+		}
+		rc, err := entry.Open()
+		if err != nil {
+			record(item, false, fmt.Errorf("opening %s: %w", entry.Filename, err))
+			continue
+		}
+		err = writeExportedItem(dir, item, historyItemExtension(item, entry.Filename), rc)
+		rc.Close()
+		record(item, false, err)
+	}
+}
+
+// historyItemExtension returns the file extension for the audio of a history item, taken from a file name
+// when available and from the item's content type otherwise.
+func historyItemExtension(item HistoryItem, name string) string {
+	if ext := strings.TrimPrefix(path.Ext(name), "."); ext != "" {
+		return ext
+	}
+	if mediaType, _, err := mime.ParseMediaType(item.ContentType); err == nil {
+		for _, f := range AudioFormats() {
+			if f.MIMEType() == mediaType {
+				return f.Extension()
+			}
+		}
+	}
+	return FormatMP3_44100_128.Extension()
+}
+
+// writeExportedItem writes the audio of a history item followed by its JSON metadata. Files are written to a
+// temporary name first so that an interrupted export never leaves a partial file behind.
+func writeExportedItem(dir string, item HistoryItem, ext string, audio io.Reader) error {
+	if err := writeFileAtomic(filepath.Join(dir, item.HistoryItemId+"."+ext), audio); err != nil {
+		return err
+	}
+	meta, err := json.MarshalIndent(item, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(dir, item.HistoryItemId+".json"), bytes.NewReader(meta))
+}
+
+func writeFileAtomic(name string, r io.Reader) error {
+	f, err := os.CreateTemp(filepath.Dir(name), filepath.Base(name)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	if err := os.Rename(f.Name(), name); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return nil
+}
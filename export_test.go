@@ -0,0 +1,165 @@
+package elevenlabs_test
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/haguro/elevenlabs-go"
+)
+
+// historyRoutes serves the history items returned by items in pages of two and their audio through the download
+// endpoint, recording the IDs of each download. Zip entries are in the reverse order of the request and are named
+// after their position unless namedByID is set.
+func historyRoutes(t *testing.T, items func() []elevenlabs.HistoryItem, namedByID bool, downloads *[][]string) map[string]http.HandlerFunc {
+	var mu sync.Mutex
+	return map[string]http.HandlerFunc{
+		"GET /history": func(w http.ResponseWriter, r *http.Request) {
+			all := items()
+			start := 0
+			if after := r.URL.Query().Get("start_after_history_item_id"); after != "" {
+				for i, h := range all {
+					if h.HistoryItemId == after {
+						start = i + 1
+					}
+				}
+			}
+			end := start + 2
+			if end > len(all) {
+				end = len(all)
+			}
+			resp := elevenlabs.GetHistoryResponse{History: all[start:end], HasMore: end < len(all)}
+			if end > start {
+				resp.LastHistoryItemId = all[end-1].HistoryItemId
+			}
+			json.NewEncoder(w).Encode(resp)
+		},
+		"POST /history/download": func(w http.ResponseWriter, r *http.Request) {
+			var req elevenlabs.DownloadHistoryRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Errorf("Server: failed to decode download request: %s", err)
+			}
+			mu.Lock()
+			*downloads = append(*downloads, req.HistoryItemIds)
+			mu.Unlock()
+			if len(req.HistoryItemIds) == 1 {
+				fmt.Fprintf(w, "audio-%s", req.HistoryItemIds[0])
+				return
+			}
+			zw := zip.NewWriter(w)
+			for i := range req.HistoryItemIds {
+				id := req.HistoryItemIds[len(req.HistoryItemIds)-1-i]
+				name := fmt.Sprintf("Voice_%d.mp3", i)
+				if namedByID {
+					name = fmt.Sprintf("Voice_%s.mp3", id)
+				}
+				f, _ := zw.Create(name)
+				fmt.Fprintf(f, "audio-%s", id)
+			}
+			zw.Close()
+		},
+	}
+}
+
+func TestExportHistory(t *testing.T) {
+	for _, namedByID := range []bool{false, true} {
+		t.Run(fmt.Sprintf("named by ID %t", namedByID), func(t *testing.T) {
+			var items []elevenlabs.HistoryItem
+			var downloads [][]string
+			for i := 1; i <= 5; i++ {
+				items = append(items, elevenlabs.HistoryItem{HistoryItemId: fmt.Sprintf("h%d", i), Text: fmt.Sprint(i), ContentType: "audio/mpeg"})
+			}
+			server := testServer(t, testServerConfig{
+				routes: historyRoutes(t, func() []elevenlabs.HistoryItem { return items }, namedByID, &downloads),
+			})
+			defer server.Close()
+			client := elevenlabs.NewMockClient(context.Background(), server.URL, mockAPIKey, mockTimeout)
+
+			dir := t.TempDir()
+			report, err := client.ExportHistory(elevenlabs.ExportOptions{Dir: dir, BatchSize: 2, Concurrency: 2})
+			if err != nil {
+				t.Fatalf("Expected no errors, got %q", err)
+			}
+			sort.Strings(report.Exported)
+			if exp := []string{"h1", "h2", "h3", "h4", "h5"}; !reflect.DeepEqual(report.Exported, exp) || len(report.Failed) > 0 {
+				t.Errorf("Expected items %v to be exported, got %+v", exp, report)
+			}
+			for _, h := range items {
+				audio, err := os.ReadFile(filepath.Join(dir, h.HistoryItemId+".mp3"))
+				if err != nil || string(audio) != "audio-"+h.HistoryItemId {
+					t.Errorf("Expected audio %q for %s, got %q (error: %v)", "audio-"+h.HistoryItemId, h.HistoryItemId, audio, err)
+				}
+				var meta elevenlabs.HistoryItem
+				b, err := os.ReadFile(filepath.Join(dir, h.HistoryItemId+".json"))
+				if err != nil || json.Unmarshal(b, &meta) != nil || !reflect.DeepEqual(meta, h) {
+					t.Errorf("Expected metadata %+v for %s, got %s (error: %v)", h, h.HistoryItemId, b, err)
+				}
+			}
+
+			// Remove one item's metadata to simulate an interrupted export, and add a new item.
+			os.Remove(filepath.Join(dir, "h2.json"))
+			items = append(items, elevenlabs.HistoryItem{HistoryItemId: "h6", ContentType: "audio/mpeg"})
+			downloads = nil
+			report, err = client.ExportHistory(elevenlabs.ExportOptions{Dir: dir, BatchSize: 2, Concurrency: 1})
+			if err != nil {
+				t.Fatalf("Expected no errors, got %q", err)
+			}
+			if exp := []string{"h2", "h6"}; !reflect.DeepEqual(report.Exported, exp) || len(report.Skipped) != 4 {
+				t.Errorf("Expected items %v to be exported and 4 to be skipped, got %+v", exp, report)
+			}
+			// Items that cannot be matched by name are downloaded again on their own.
+			exp := [][]string{{"h2", "h6"}}
+			if !namedByID {
+				exp = append(exp, []string{"h2"}, []string{"h6"})
+			}
+			if !reflect.DeepEqual(downloads, exp) {
+				t.Errorf("Expected downloads %v, got %v", exp, downloads)
+			}
+		})
+	}
+}
+
+func TestExportHistoryFailures(t *testing.T) {
+	// A zip file holding a single entry for two requested items.
+	zipData := makeZip(t, "audio.mp3", "audio")
+	server := testServer(t, testServerConfig{routes: map[string]http.HandlerFunc{
+		"GET /history": func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"history":[{"history_item_id":"h1","voice_id":"v1"},{"history_item_id":"h2","voice_id":"v2"},{"history_item_id":"h3","voice_id":"v1"}],"has_more":false}`)
+		},
+		"POST /history/download": func(w http.ResponseWriter, r *http.Request) {
+			w.Write(zipData)
+		},
+	}})
+	defer server.Close()
+	client := elevenlabs.NewMockClient(context.Background(), server.URL, mockAPIKey, mockTimeout)
+
+	var mu sync.Mutex
+	var notified []string
+	report, err := client.ExportHistory(elevenlabs.ExportOptions{
+		Dir:    t.TempDir(),
+		Filter: elevenlabs.HistoryFilter{VoiceID: "v1"},
+		OnItem: func(item elevenlabs.HistoryItem, skipped bool, err error) {
+			mu.Lock()
+			notified = append(notified, item.HistoryItemId)
+			mu.Unlock()
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+	if len(report.Failed) != 2 || report.Failed["h1"] == nil || report.Failed["h3"] == nil || len(report.Exported) != 0 {
+		t.Errorf("Expected h1 and h3 to fail, got %+v", report)
+	}
+	sort.Strings(notified)
+	if exp := []string{"h1", "h3"}; !reflect.DeepEqual(notified, exp) {
+		t.Errorf("Expected OnItem to be called for %v, got %v", exp, notified)
+	}
+}
@@ -0,0 +1,245 @@
+package elevenlabs
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultSubscriptionCacheTTL is the default time the subscription used to validate output formats is cached by a
+// Client before being retrieved again.
+const DefaultSubscriptionCacheTTL = 5 * time.Minute
+
+// AudioFormat represents an audio output format that can be requested from the text to speech endpoints
+// via the OutputFormat QueryFunc.
+type AudioFormat string
+
+// AudioCodec represents the codec (or encoding) used by an AudioFormat.
+type AudioCodec string
+
+const (
+	CodecMP3  AudioCodec = "mp3"
+	CodecPCM  AudioCodec = "pcm"
+	CodecULaw AudioCodec = "ulaw"
+	CodecALaw AudioCodec = "alaw"
+	CodecOpus AudioCodec = "opus"
+)
+
+const (
+	FormatMP3_22050_32   AudioFormat = "mp3_22050_32"
+	FormatMP3_44100_32   AudioFormat = "mp3_44100_32"
+	FormatMP3_44100_64   AudioFormat = "mp3_44100_64"
+	FormatMP3_44100_96   AudioFormat = "mp3_44100_96"
+	FormatMP3_44100_128  AudioFormat = "mp3_44100_128"
+	FormatMP3_44100_192  AudioFormat = "mp3_44100_192"
+	FormatPCM_8000       AudioFormat = "pcm_8000"
+	FormatPCM_16000      AudioFormat = "pcm_16000"
+	FormatPCM_22050      AudioFormat = "pcm_22050"
+	FormatPCM_24000      AudioFormat = "pcm_24000"
+	FormatPCM_44100      AudioFormat = "pcm_44100"
+	FormatULaw_8000      AudioFormat = "ulaw_8000"
+	FormatALaw_8000      AudioFormat = "alaw_8000"
+	FormatOpus_48000_32  AudioFormat = "opus_48000_32"
+	FormatOpus_48000_64  AudioFormat = "opus_48000_64"
+	FormatOpus_48000_96  AudioFormat = "opus_48000_96"
+	FormatOpus_48000_128 AudioFormat = "opus_48000_128"
+	FormatOpus_48000_192 AudioFormat = "opus_48000_192"
+
+	// DefaultAudioFormat is the format used by the API when no output format is requested.
+	DefaultAudioFormat = FormatMP3_44100_128
+)
+
+// Subscription tiers as reported by the Tier field of Subscription.
+const (
+	TierFree                 = "free"
+	TierStarter              = "starter"
+	TierCreator              = "creator"
+	TierIndependentPublisher = "independent_publisher"
+	TierGrowingBusiness      = "growing_business"
+	TierEnterprise           = "enterprise"
+)
+
+// tierRanks orders the known subscription tiers. Newer tier names are mapped to the rank of
+// their older equivalents.
+var tierRanks = map[string]int{
+	TierFree:                 0,
+	TierStarter:              1,
+	TierCreator:              2,
+	TierIndependentPublisher: 3,
+	"pro":                    3,
+	TierGrowingBusiness:      4,
+	"scale":                  4,
+	"business":               5,
+	TierEnterprise:           6,
+}
+
+// AudioFormatInfo contains the metadata of an AudioFormat.
+type AudioFormatInfo struct {
+	Codec AudioCodec
+	// SampleRate is the sample rate in Hz.
+	SampleRate int
+	// Bitrate is the bitrate in bits per second.
+	Bitrate int
+	// BytesPerSample is the size in bytes of a single (mono) sample for uncompressed formats
+	// and 0 for compressed ones.
+	BytesPerSample int
+	MIMEType       string
+	// Extension is the file extension commonly used for the format, without the leading dot.
+	Extension string
+	// MinTier is the lowest subscription tier allowed to request the format.
+	MinTier string
+}
+
+func mp3Info(sampleRate, kbps int, minTier string) AudioFormatInfo {
+	return AudioFormatInfo{Codec: CodecMP3, SampleRate: sampleRate, Bitrate: kbps * 1000, MIMEType: "audio/mpeg", Extension: "mp3", MinTier: minTier}
+}
+
+func pcmInfo(sampleRate int, minTier string) AudioFormatInfo {
+	return AudioFormatInfo{Codec: CodecPCM, SampleRate: sampleRate, Bitrate: sampleRate * 16, BytesPerSample: 2, MIMEType: "audio/pcm", Extension: "pcm", MinTier: minTier}
+}
+
+func opusInfo(kbps int, minTier string) AudioFormatInfo {
+	return AudioFormatInfo{Codec: CodecOpus, SampleRate: 48000, Bitrate: kbps * 1000, MIMEType: "audio/ogg", Extension: "opus", MinTier: minTier}
+}
+
+var audioFormats = map[AudioFormat]AudioFormatInfo{
+	FormatMP3_22050_32:   mp3Info(22050, 32, TierFree),
+	FormatMP3_44100_32:   mp3Info(44100, 32, TierFree),
+	FormatMP3_44100_64:   mp3Info(44100, 64, TierFree),
+	FormatMP3_44100_96:   mp3Info(44100, 96, TierFree),
+	FormatMP3_44100_128:  mp3Info(44100, 128, TierFree),
+	FormatMP3_44100_192:  mp3Info(44100, 192, TierCreator),
+	FormatPCM_8000:       pcmInfo(8000, TierFree),
+	FormatPCM_16000:      pcmInfo(16000, TierFree),
+	FormatPCM_22050:      pcmInfo(22050, TierFree),
+	FormatPCM_24000:      pcmInfo(24000, TierFree),
+	FormatPCM_44100:      pcmInfo(44100, TierIndependentPublisher),
+	FormatULaw_8000:      {Codec: CodecULaw, SampleRate: 8000, Bitrate: 64000, BytesPerSample: 1, MIMEType: "audio/basic", Extension: "ulaw", MinTier: TierFree},
+	FormatALaw_8000:      {Codec: CodecALaw, SampleRate: 8000, Bitrate: 64000, BytesPerSample: 1, MIMEType: "audio/x-alaw-basic", Extension: "alaw", MinTier: TierFree},
+	FormatOpus_48000_32:  opusInfo(32, TierFree),
+	FormatOpus_48000_64:  opusInfo(64, TierFree),
+	FormatOpus_48000_96:  opusInfo(96, TierFree),
+	FormatOpus_48000_128: opusInfo(128, TierFree),
+	FormatOpus_48000_192: opusInfo(192, TierCreator),
+}
+
+// AudioFormats returns all the known audio formats sorted by name.
+func AudioFormats() []AudioFormat {
+	formats := make([]AudioFormat, 0, len(audioFormats))
+	for f := range audioFormats {
+		formats = append(formats, f)
+	}
+	sort.Slice(formats, func(i, j int) bool { return formats[i] < formats[j] })
+	return formats
+}
+
+// Info returns the metadata of the audio format, and a bool that is false if the format is unknown.
+func (f AudioFormat) Info() (AudioFormatInfo, bool) {
+	info, ok := audioFormats[f]
+	return info, ok
+}
+
+// Valid reports whether the audio format is a known format.
+func (f AudioFormat) Valid() bool {
+	_, ok := audioFormats[f]
+	return ok
+}
+
+// Codec returns the codec of the audio format, or an empty string if the format is unknown.
+func (f AudioFormat) Codec() AudioCodec { return audioFormats[f].Codec }
+
+// SampleRate returns the sample rate of the audio format in Hz, or 0 if the format is unknown.
+func (f AudioFormat) SampleRate() int { return audioFormats[f].SampleRate }
+
+// Bitrate returns the bitrate of the audio format in bits per second, or 0 if the format is unknown.
+func (f AudioFormat) Bitrate() int { return audioFormats[f].Bitrate }
+
+// BytesPerSample returns the size of a single sample in bytes for uncompressed formats, or 0 otherwise.
+func (f AudioFormat) BytesPerSample() int { return audioFormats[f].BytesPerSample }
+
+// MIMEType returns the MIME type of the audio format, or "application/octet-stream" if the format is unknown.
+func (f AudioFormat) MIMEType() string {
+	if info, ok := audioFormats[f]; ok {
+		return info.MIMEType
+	}
+	return "application/octet-stream"
+}
+
+// Extension returns the file extension (without the leading dot) of the audio format, or "bin" if the format
+// is unknown.
+func (f AudioFormat) Extension() string {
+	if info, ok := audioFormats[f]; ok {
+		return info.Extension
+	}
+	return "bin"
+}
+
+// MinTier returns the lowest subscription tier allowed to request the audio format.
+func (f AudioFormat) MinTier() string { return audioFormats[f].MinTier }
+
+// ValidateTier checks whether the audio format can be requested with a subscription of a given tier.
+//
+// It returns an error wrapping ErrUnknownAudioFormat if the format is not known, or an error wrapping
+// ErrAudioFormatTier if the tier is below the format's minimum tier. Unrecognized tiers are not rejected
+// as they are likely to be tiers introduced after this library was released.
+func (f AudioFormat) ValidateTier(tier string) error {
+	info, ok := audioFormats[f]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownAudioFormat, f)
+	}
+	rank, ok := tierRanks[strings.ToLower(tier)]
+	if !ok {
+		return nil
+	}
+	if rank < tierRanks[info.MinTier] {
+		return fmt.Errorf("%w: %q requires %q tier or above, got %q", ErrAudioFormatTier, f, info.MinTier, tier)
+	}
+	return nil
+}
+
+// ValidateOutputFormat checks whether the user's subscription allows requesting a given audio format.
+// TextToSpeech and TextToSpeechStream already perform this check when using the OutputFormat QueryFunc, so
+// calling it is only needed to find out ahead of time. The subscription is cached (see SetSubscriptionCacheTTL).
+//
+// It takes an AudioFormat argument that represents the format to be validated.
+//
+// It returns nil if the format can be used, or an error otherwise.
+func (c *Client) ValidateOutputFormat(format AudioFormat) error {
+	if !format.Valid() {
+		return fmt.Errorf("%w: %q", ErrUnknownAudioFormat, format)
+	}
+	sub, err := c.subscription.get(c.GetSubscription)
+	if err != nil {
+		return err
+	}
+	return format.ValidateTier(sub.Tier)
+}
+
+// SetSubscriptionCacheTTL sets the time the subscription used to validate output formats is cached for.
+//
+// It takes a time.Duration argument that represents the new TTL. A value of 0 or less disables caching.
+func (c *Client) SetSubscriptionCacheTTL(ttl time.Duration) {
+	c.subscription.setTTL(ttl)
+}
+
+// checkOutputFormat validates the output format set by a list of QueryFunc against the user's subscription before a
+// text to speech request is sent. Formats that are unknown or available to all tiers are not checked. An error is
+// returned if the subscription cannot be retrieved, so that the check is never silently skipped.
+func (c *Client) checkOutputFormat(queries []QueryFunc) error {
+	q := url.Values{}
+	for _, qf := range queries {
+		qf(&q)
+	}
+	format := AudioFormat(q.Get("output_format"))
+	info, ok := format.Info()
+	if !ok || info.MinTier == TierFree {
+		return nil
+	}
+	sub, err := c.subscription.get(c.GetSubscription)
+	if err != nil {
+		return fmt.Errorf("checking output format %q: %w", format, err)
+	}
+	return format.ValidateTier(sub.Tier)
+}
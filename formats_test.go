@@ -0,0 +1,196 @@
+package elevenlabs_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/haguro/elevenlabs-go"
+)
+
+func TestAudioFormatInfo(t *testing.T) {
+	testCases := []struct {
+		format         elevenlabs.AudioFormat
+		codec          elevenlabs.AudioCodec
+		sampleRate     int
+		bitrate        int
+		bytesPerSample int
+		mimeType       string
+		ext            string
+		minTier        string
+	}{
+		{elevenlabs.FormatMP3_44100_128, elevenlabs.CodecMP3, 44100, 128000, 0, "audio/mpeg", "mp3", elevenlabs.TierFree},
+		{elevenlabs.FormatMP3_44100_192, elevenlabs.CodecMP3, 44100, 192000, 0, "audio/mpeg", "mp3", elevenlabs.TierCreator},
+		{elevenlabs.FormatPCM_16000, elevenlabs.CodecPCM, 16000, 256000, 2, "audio/pcm", "pcm", elevenlabs.TierFree},
+		{elevenlabs.FormatPCM_44100, elevenlabs.CodecPCM, 44100, 705600, 2, "audio/pcm", "pcm", elevenlabs.TierIndependentPublisher},
+		{elevenlabs.FormatULaw_8000, elevenlabs.CodecULaw, 8000, 64000, 1, "audio/basic", "ulaw", elevenlabs.TierFree},
+		{elevenlabs.FormatALaw_8000, elevenlabs.CodecALaw, 8000, 64000, 1, "audio/x-alaw-basic", "alaw", elevenlabs.TierFree},
+		{elevenlabs.FormatOpus_48000_64, elevenlabs.CodecOpus, 48000, 64000, 0, "audio/ogg", "opus", elevenlabs.TierFree},
+	}
+	for _, tc := range testCases {
+		t.Run(string(tc.format), func(t *testing.T) {
+			if !tc.format.Valid() {
+				t.Fatalf("Expected %q to be a valid format", tc.format)
+			}
+			info, ok := tc.format.Info()
+			if !ok {
+				t.Fatalf("Expected Info to return metadata for %q", tc.format)
+			}
+			exp := elevenlabs.AudioFormatInfo{
+				Codec:          tc.codec,
+				SampleRate:     tc.sampleRate,
+				Bitrate:        tc.bitrate,
+				BytesPerSample: tc.bytesPerSample,
+				MIMEType:       tc.mimeType,
+				Extension:      tc.ext,
+				MinTier:        tc.minTier,
+			}
+			if info != exp {
+				t.Errorf("Expected info %+v, got %+v", exp, info)
+			}
+			if tc.format.Codec() != tc.codec || tc.format.SampleRate() != tc.sampleRate || tc.format.Bitrate() != tc.bitrate ||
+				tc.format.BytesPerSample() != tc.bytesPerSample || tc.format.MIMEType() != tc.mimeType ||
+				tc.format.Extension() != tc.ext || tc.format.MinTier() != tc.minTier {
+				t.Errorf("Expected accessor methods to match info %+v", exp)
+			}
+		})
+	}
+
+	unknown := elevenlabs.AudioFormat("wav_1")
+	if unknown.Valid() {
+		t.Error("Expected unknown format to be invalid")
+	}
+	if unknown.MIMEType() != "application/octet-stream" || unknown.Extension() != "bin" {
+		t.Errorf("Unexpected fallbacks for unknown format: %q, %q", unknown.MIMEType(), unknown.Extension())
+	}
+	if n := len(elevenlabs.AudioFormats()); n != 18 {
+		t.Errorf("Expected 18 known formats, got %d", n)
+	}
+}
+
+func TestAudioFormatValidateTier(t *testing.T) {
+	testCases := []struct {
+		name   string
+		format elevenlabs.AudioFormat
+		tier   string
+		expErr error
+	}{
+		{"free tier default format", elevenlabs.FormatMP3_44100_128, elevenlabs.TierFree, nil},
+		{"free tier 192kbps", elevenlabs.FormatMP3_44100_192, elevenlabs.TierFree, elevenlabs.ErrAudioFormatTier},
+		{"creator tier 192kbps", elevenlabs.FormatMP3_44100_192, elevenlabs.TierCreator, nil},
+		{"creator tier pcm 44.1kHz", elevenlabs.FormatPCM_44100, elevenlabs.TierCreator, elevenlabs.ErrAudioFormatTier},
+		{"pro tier pcm 44.1kHz", elevenlabs.FormatPCM_44100, "pro", nil},
+		{"unknown tier", elevenlabs.FormatPCM_44100, "some_new_tier", nil},
+		{"unknown format", elevenlabs.AudioFormat("flac_96000"), elevenlabs.TierEnterprise, elevenlabs.ErrUnknownAudioFormat},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.format.ValidateTier(tc.tier)
+			if tc.expErr == nil && err != nil {
+				t.Errorf("Expected no errors, got %q", err)
+			}
+			if tc.expErr != nil && !errors.Is(err, tc.expErr) {
+				t.Errorf("Expected error %q, got %v", tc.expErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateOutputFormat(t *testing.T) {
+	server := testServer(t, testServerConfig{
+		expectedMethod:      http.MethodGet,
+		expectedContentType: contentTypeJSON,
+		expectedAccept:      "*/*",
+		statusCode:          http.StatusOK,
+		responseBody:        []byte(`{"tier": "starter"}`),
+	})
+	defer server.Close()
+	client := elevenlabs.NewMockClient(context.Background(), server.URL, mockAPIKey, mockTimeout)
+	if err := client.ValidateOutputFormat(elevenlabs.FormatPCM_24000); err != nil {
+		t.Errorf("Expected no errors, got %q", err)
+	}
+	if err := client.ValidateOutputFormat(elevenlabs.FormatMP3_44100_192); !errors.Is(err, elevenlabs.ErrAudioFormatTier) {
+		t.Errorf("Expected error %q, got %v", elevenlabs.ErrAudioFormatTier, err)
+	}
+}
+
+func TestTextToSpeechOutputFormatTier(t *testing.T) {
+	log := &requestLog{}
+	server := testServer(t, testServerConfig{
+		routes: map[string]http.HandlerFunc{
+			"GET /user/subscription": func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"tier": "starter"}`))
+			},
+			"POST /text-to-speech/v1": func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte("audio"))
+			},
+			"POST /text-to-speech/v1/stream": func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte("audio"))
+			},
+		},
+		log: log,
+	})
+	defer server.Close()
+	client := elevenlabs.NewMockClient(context.Background(), server.URL, mockAPIKey, mockTimeout)
+	ttsReq := elevenlabs.TextToSpeechRequest{Text: "Hello"}
+
+	if _, err := client.TextToSpeech("v1", ttsReq, elevenlabs.OutputFormat(elevenlabs.FormatPCM_24000)); err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+	if gets := log.count("GET /user/subscription"); gets != 0 {
+		t.Errorf("Expected no subscription requests for a format available to all tiers, got %d", gets)
+	}
+
+	if _, err := client.TextToSpeech("v1", ttsReq, elevenlabs.OutputFormat(elevenlabs.FormatMP3_44100_192)); !errors.Is(err, elevenlabs.ErrAudioFormatTier) {
+		t.Errorf("Expected error %q, got %v", elevenlabs.ErrAudioFormatTier, err)
+	}
+	if err := client.TextToSpeechStream(io.Discard, "v1", ttsReq, elevenlabs.OutputFormat(elevenlabs.FormatPCM_44100)); !errors.Is(err, elevenlabs.ErrAudioFormatTier) {
+		t.Errorf("Expected error %q, got %v", elevenlabs.ErrAudioFormatTier, err)
+	}
+	if _, _, err := client.RecordedTextToSpeech("v1", ttsReq, elevenlabs.OutputFormat(elevenlabs.FormatOpus_48000_192)); !errors.Is(err, elevenlabs.ErrAudioFormatTier) {
+		t.Errorf("Expected error %q, got %v", elevenlabs.ErrAudioFormatTier, err)
+	}
+	if err := client.ValidateOutputFormat(elevenlabs.FormatMP3_44100_192); !errors.Is(err, elevenlabs.ErrAudioFormatTier) {
+		t.Errorf("Expected error %q, got %v", elevenlabs.ErrAudioFormatTier, err)
+	}
+	if gets := log.count("GET /user/subscription"); gets != 1 {
+		t.Errorf("Expected the subscription to be retrieved once, got %d", gets)
+	}
+	if posts := log.count("POST /text-to-speech/v1") + log.count("POST /text-to-speech/v1/stream"); posts != 1 {
+		t.Errorf("Expected a single text to speech request, got %d", posts)
+	}
+
+	client.SetSubscriptionCacheTTL(0)
+	if err := client.ValidateOutputFormat(elevenlabs.FormatMP3_44100_192); !errors.Is(err, elevenlabs.ErrAudioFormatTier) {
+		t.Errorf("Expected error %q, got %v", elevenlabs.ErrAudioFormatTier, err)
+	}
+	if gets := log.count("GET /user/subscription"); gets != 2 {
+		t.Errorf("Expected the subscription to be retrieved again with caching disabled, got %d requests", gets)
+	}
+}
+
+func TestTextToSpeechOutputFormatSubscriptionError(t *testing.T) {
+	log := &requestLog{}
+	server := testServer(t, testServerConfig{
+		routes: map[string]http.HandlerFunc{
+			"GET /user/subscription": func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"detail":{"status":"invalid_api_key","message":"Invalid API key"}}`))
+			},
+		},
+		log: log,
+	})
+	defer server.Close()
+	client := elevenlabs.NewMockClient(context.Background(), server.URL, mockAPIKey, mockTimeout)
+
+	_, err := client.TextToSpeech("v1", elevenlabs.TextToSpeechRequest{Text: "Hello"}, elevenlabs.OutputFormat(elevenlabs.FormatMP3_44100_192))
+	if err == nil {
+		t.Fatal("Expected an error when the subscription cannot be retrieved, got nil")
+	}
+	if exp := []string{"GET /user/subscription"}; !reflect.DeepEqual(log.list(), exp) {
+		t.Errorf("Expected requests %v, got %v", exp, log.list())
+	}
+}
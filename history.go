@@ -0,0 +1,140 @@
+package elevenlabs
+
+import (
+	"strings"
+	"time"
+)
+
+// Possible values of the HistorySource query.
+const (
+	HistorySourceTTS = "TTS"
+	HistorySourceSTS = "STS"
+)
+
+// CharacterCount returns the number of characters the history item was charged for.
+func (h HistoryItem) CharacterCount() int {
+	return h.CharacterCountChangeTo - h.CharacterCountChangeFrom
+}
+
+// Time returns the creation time of the history item.
+func (h HistoryItem) Time() time.Time {
+	return time.Unix(int64(h.DateUnix), 0)
+}
+
+// HasFeedback reports whether any feedback was given for the history item.
+func (h HistoryItem) HasFeedback() bool {
+	f := h.Feedback
+	return f.ThumbsUp || f.Feedback != "" || f.Emotions || f.InaccurateClone || f.Glitches || f.AudioQuality || f.Other
+}
+
+// HistoryFilter represents a set of conditions that history items must meet. Zero valued fields are ignored,
+// so the zero value of HistoryFilter matches every history item.
+//
+// The VoiceID, ModelID, After and Before fields are also sent to the API by SearchHistory to reduce the number
+// of pages retrieved. The other fields are only applied to the retrieved history items.
+type HistoryFilter struct {
+	VoiceID string
+	ModelID string
+	// After and Before restrict the creation time of the history items to [After, Before).
+	After  time.Time
+	Before time.Time
+	// TextContains is matched case-insensitively against the history item's text.
+	TextContains string
+	// State matches the history item's state (e.g. "created").
+	State string
+	// HasFeedback, if set, restricts the history items to those with or without feedback.
+	HasFeedback *bool
+	// ThumbsUp, if set, restricts the history items to those with a matching thumbs up feedback.
+	ThumbsUp *bool
+	// MinCharacters and MaxCharacters restrict the number of characters the history items were charged for,
+	// as returned by HistoryItem.CharacterCount. A value of 0 means no limit.
+	MinCharacters int
+	MaxCharacters int
+}
+
+// Match reports whether a given history item meets all the conditions of the filter.
+func (f HistoryFilter) Match(h HistoryItem) bool {
+	if f.VoiceID != "" && h.VoiceId != f.VoiceID {
+		return false
+	}
+	if f.ModelID != "" && h.ModelId != f.ModelID {
+		return false
+	}
+	if !f.After.IsZero() && int64(h.DateUnix) < f.After.Unix() {
+		return false
+	}
+	if !f.Before.IsZero() && int64(h.DateUnix) >= f.Before.Unix() {
+		return false
+	}
+	if f.TextContains != "" && !strings.Contains(strings.ToLower(h.Text), strings.ToLower(f.TextContains)) {
+		return false
+	}
+	if f.State != "" && h.State != f.State {
+		return false
+	}
+	if f.HasFeedback != nil && h.HasFeedback() != *f.HasFeedback {
+		return false
+	}
+	if f.ThumbsUp != nil && h.Feedback.ThumbsUp != *f.ThumbsUp {
+		return false
+	}
+	count := h.CharacterCount()
+	if f.MinCharacters > 0 && count < f.MinCharacters {
+		return false
+	}
+	if f.MaxCharacters > 0 && count > f.MaxCharacters {
+		return false
+	}
+	return true
+}
+
+// Queries returns the QueryFuncs that apply the filter's server side conditions to a GetHistory or
+// HistoryIterator request.
+func (f HistoryFilter) Queries() []QueryFunc {
+	var queries []QueryFunc
+	if f.VoiceID != "" {
+		queries = append(queries, HistoryVoiceID(f.VoiceID))
+	}
+	if f.ModelID != "" {
+		queries = append(queries, HistoryModelID(f.ModelID))
+	}
+	if !f.After.IsZero() {
+		queries = append(queries, HistoryAfter(f.After))
+	}
+	if !f.Before.IsZero() {
+		queries = append(queries, HistoryBefore(f.Before))
+	}
+	return queries
+}
+
+// FilterHistory returns the history items that match a given filter, in their original order.
+func FilterHistory(items []HistoryItem, filter HistoryFilter) []HistoryItem {
+	var matched []HistoryItem
+	for _, h := range items {
+		if filter.Match(h) {
+			matched = append(matched, h)
+		}
+	}
+	return matched
+}
+
+// SearchHistory retrieves all the history items that match a given filter, walking through all history pages.
+//
+// It takes a HistoryFilter argument that the history items must match and an optional list of QueryFunc
+// 'queries' that are applied to every page request, such as PageSize or HistorySource.
+//
+// It returns a slice of the matching HistoryItem or an error. Every retrieved history item is also matched
+// against the filter locally, so the results are correct even if the API ignores some of the queries.
+func (c *Client) SearchHistory(filter HistoryFilter, queries ...QueryFunc) ([]HistoryItem, error) {
+	it := c.HistoryIterator(append(filter.Queries(), queries...)...)
+	var matched []HistoryItem
+	for it.Next(c.ctx) {
+		if h := it.Item(); filter.Match(h) {
+			matched = append(matched, h)
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return matched, nil
+}
@@ -0,0 +1,106 @@
+package elevenlabs_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/haguro/elevenlabs-go"
+)
+
+func historyIDs(items []elevenlabs.HistoryItem) []string {
+	ids := make([]string, len(items))
+	for i, h := range items {
+		ids[i] = h.HistoryItemId
+	}
+	return ids
+}
+
+var testHistoryItems = []elevenlabs.HistoryItem{
+	{HistoryItemId: "h1", VoiceId: "v1", ModelId: "m1", DateUnix: 1000, Text: "Hello World", State: "created", CharacterCountChangeFrom: 0, CharacterCountChangeTo: 11},
+	{HistoryItemId: "h2", VoiceId: "v2", ModelId: "m1", DateUnix: 2000, Text: "Goodbye", State: "created", CharacterCountChangeFrom: 11, CharacterCountChangeTo: 18, Feedback: elevenlabs.Feedback{ThumbsUp: true}},
+	{HistoryItemId: "h3", VoiceId: "v1", ModelId: "m2", DateUnix: 3000, Text: "hello again, world", State: "deleted", CharacterCountChangeFrom: 18, CharacterCountChangeTo: 36, Feedback: elevenlabs.Feedback{Glitches: true}},
+}
+
+func TestHistoryFilter(t *testing.T) {
+	yes, no := true, false
+	testCases := []struct {
+		name   string
+		filter elevenlabs.HistoryFilter
+		expIDs []string
+	}{
+		{"Zero filter", elevenlabs.HistoryFilter{}, []string{"h1", "h2", "h3"}},
+		{"Voice ID", elevenlabs.HistoryFilter{VoiceID: "v1"}, []string{"h1", "h3"}},
+		{"Model ID", elevenlabs.HistoryFilter{ModelID: "m1"}, []string{"h1", "h2"}},
+		{"Date range", elevenlabs.HistoryFilter{After: time.Unix(2000, 0), Before: time.Unix(3000, 0)}, []string{"h2"}},
+		{"Text contains", elevenlabs.HistoryFilter{TextContains: "HELLO"}, []string{"h1", "h3"}},
+		{"State", elevenlabs.HistoryFilter{State: "deleted"}, []string{"h3"}},
+		{"Has feedback", elevenlabs.HistoryFilter{HasFeedback: &yes}, []string{"h2", "h3"}},
+		{"No feedback", elevenlabs.HistoryFilter{HasFeedback: &no}, []string{"h1"}},
+		{"Thumbs up", elevenlabs.HistoryFilter{ThumbsUp: &yes}, []string{"h2"}},
+		{"Character range", elevenlabs.HistoryFilter{MinCharacters: 8, MaxCharacters: 12}, []string{"h1"}},
+		{"Combined", elevenlabs.HistoryFilter{VoiceID: "v1", TextContains: "world", MinCharacters: 12}, []string{"h3"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := historyIDs(elevenlabs.FilterHistory(testHistoryItems, tc.filter))
+			if !reflect.DeepEqual(got, tc.expIDs) {
+				t.Errorf("Expected history items %v, got %v", tc.expIDs, got)
+			}
+		})
+	}
+}
+
+func TestSearchHistory(t *testing.T) {
+	var gotQueries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQueries = append(gotQueries, r.URL.RawQuery)
+		// Simulate an API that ignores the filters to check that matching is also done locally.
+		if r.URL.Query().Get("start_after_history_item_id") == "" {
+			fmt.Fprint(w, `{"history":[{"history_item_id":"h1","voice_id":"v1","text":"one"},{"history_item_id":"h2","voice_id":"v2","text":"two"}],"last_history_item_id":"h2","has_more":true}`)
+			return
+		}
+		fmt.Fprint(w, `{"history":[{"history_item_id":"h3","voice_id":"v1","text":"three"}],"last_history_item_id":"h3","has_more":false}`)
+	}))
+	defer server.Close()
+	client := elevenlabs.NewMockClient(context.Background(), server.URL, mockAPIKey, mockTimeout)
+
+	filter := elevenlabs.HistoryFilter{VoiceID: "v1", After: time.Unix(1000, 0), Before: time.Unix(2000, 0), TextContains: "e"}
+	items, err := client.SearchHistory(filter, elevenlabs.HistorySource(elevenlabs.HistorySourceTTS))
+	if err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+	// Items without a date are excluded by the date range.
+	if len(items) != 0 {
+		t.Errorf("Expected no history items, got %v", historyIDs(items))
+	}
+
+	gotQueries = nil
+	items, err = client.SearchHistory(elevenlabs.HistoryFilter{VoiceID: "v1", TextContains: "e"})
+	if err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+	if exp := []string{"h1", "h3"}; !reflect.DeepEqual(historyIDs(items), exp) {
+		t.Errorf("Expected history items %v, got %v", exp, historyIDs(items))
+	}
+	if exp := []string{"voice_id=v1", "start_after_history_item_id=h2&voice_id=v1"}; !reflect.DeepEqual(gotQueries, exp) {
+		t.Errorf("Expected queries %v, got %v", exp, gotQueries)
+	}
+}
+
+func TestHistoryFilterQueries(t *testing.T) {
+	filter := elevenlabs.HistoryFilter{VoiceID: "v1", ModelID: "m1", After: time.Unix(1000, 0), Before: time.Unix(2000, 0), State: "created"}
+	q := url.Values{}
+	for _, qf := range append(filter.Queries(), elevenlabs.HistorySource(elevenlabs.HistorySourceSTS), elevenlabs.HistorySearch("hello")) {
+		qf(&q)
+	}
+	exp := "date_after_unix=1000&date_before_unix=2000&model_id=m1&search=hello&source=STS&voice_id=v1"
+	if got := q.Encode(); got != exp {
+		t.Errorf("Expected query string %q, got %q", exp, got)
+	}
+}
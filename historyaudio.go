@@ -0,0 +1,140 @@
+package elevenlabs
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+)
+
+var zipMagic = []byte("PK\x03\x04")
+
+// HistoryAudioEntry represents the audio file of a single history item in downloaded history audio.
+type HistoryAudioEntry struct {
+	// HistoryItemID is the ID of the history item the file belongs to, or an empty string if the file could
+	// not be matched to any of the requested history items.
+	HistoryItemID string
+	// Filename is the name of the file in the zip file. It is empty when the audio was not packed in a zip file,
+	// in which case its format is given by the ContentType of the history item.
+	Filename string
+
+	open func() (io.ReadCloser, error)
+}
+
+// Open opens the (uncompressed) audio data of the entry for reading. Zip entries are only decompressed when
+// opened, and each entry can be opened several times.
+//
+// It returns an io.ReadCloser that must be closed by the caller, or an error.
+func (e HistoryAudioEntry) Open() (io.ReadCloser, error) {
+	if e.open == nil {
+		return nil, errors.New("history audio entry has no data")
+	}
+	return e.open()
+}
+
+// HistoryAudio represents the audio returned when downloading history items, which is either a single audio
+// file or a zip file packing several audio files.
+type HistoryAudio struct {
+	// Zip is true if the audio was packed in a zip file.
+	Zip     bool
+	Entries []HistoryAudioEntry
+}
+
+// Entry returns the entry of a given history item and whether it was found.
+func (a HistoryAudio) Entry(historyItemID string) (HistoryAudioEntry, bool) {
+	for _, e := range a.Entries {
+		if e.HistoryItemID == historyItemID {
+			return e, true
+		}
+	}
+	return HistoryAudioEntry{}, false
+}
+
+// ParseHistoryAudio parses the audio data returned by DownloadHistoryAudio.
+//
+// It takes a byte slice argument containing the downloaded data and a slice of strings that represents the
+// history item IDs passed in the DownloadHistoryRequest, in the same order.
+//
+// It returns a HistoryAudio object or an error if the data is a malformed zip file.
+func ParseHistoryAudio(data []byte, historyItemIDs []string) (HistoryAudio, error) {
+	return OpenHistoryAudio(bytes.NewReader(data), int64(len(data)), historyItemIDs)
+}
+
+// OpenHistoryAudio works like ParseHistoryAudio but reads the downloaded data from an io.ReaderAt of a given
+// size, such as the *os.File written to by DownloadHistoryAudioStream.
+//
+// Zip entries are matched to history items by the history item ID contained in their names. Entries whose names
+// contain none of the history item IDs are left with an empty HistoryItemID.
+func OpenHistoryAudio(r io.ReaderAt, size int64, historyItemIDs []string) (HistoryAudio, error) {
+	magic := make([]byte, len(zipMagic))
+	if n, _ := r.ReadAt(magic, 0); n < len(magic) || !bytes.Equal(magic, zipMagic) {
+		// A single history item is returned as an audio file in the format it was generated in.
+		entry := HistoryAudioEntry{open: func() (io.ReadCloser, error) {
+			return io.NopCloser(io.NewSectionReader(r, 0, size)), nil
+		}}
+		if len(historyItemIDs) == 1 {
+			entry.HistoryItemID = historyItemIDs[0]
+		}
+		return HistoryAudio{Entries: []HistoryAudioEntry{entry}}, nil
+	}
+
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return HistoryAudio{}, err
+	}
+	audio := HistoryAudio{Zip: true}
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		audio.Entries = append(audio.Entries, HistoryAudioEntry{Filename: f.Name, open: f.Open})
+	}
+
+	matched := make([]bool, len(historyItemIDs))
+	for i := range audio.Entries {
+		e := &audio.Entries[i]
+		for j, id := range historyItemIDs {
+			if !matched[j] && strings.Contains(path.Base(e.Filename), id) {
+				e.HistoryItemID = id
+				matched[j] = true
+				break
+			}
+		}
+	}
+	return audio, nil
+}
+
+// DownloadHistoryAudioFiles downloads the audio data for one or more history items and returns it as
+// individual files.
+//
+// It takes a DownloadHistoryRequest argument that specifies the history item(s) to download.
+//
+// It returns a HistoryAudio object whose entries hold the audio file of each history item, or an error.
+func (c *Client) DownloadHistoryAudioFiles(dlReq DownloadHistoryRequest) (HistoryAudio, error) {
+	data, err := c.DownloadHistoryAudio(dlReq)
+	if err != nil {
+		return HistoryAudio{}, err
+	}
+	return ParseHistoryAudio(data, dlReq.HistoryItemIds)
+}
+
+// DownloadHistoryAudioStream downloads the audio data for one or more history items and writes it to a given
+// io.Writer as it is received rather than buffering it in memory.
+//
+// It takes an io.Writer argument to which the data is written and a DownloadHistoryRequest argument that
+// specifies the history item(s) to download. As with DownloadHistoryAudio, the data is an audio file or a zip
+// file. When written to a file, it can then be passed to OpenHistoryAudio to access the individual files.
+//
+// It returns nil if successful or an error otherwise.
+func (c *Client) DownloadHistoryAudioStream(w io.Writer, dlReq DownloadHistoryRequest) error {
+	reqBody, err := json.Marshal(dlReq)
+	if err != nil {
+		return err
+	}
+	return c.doRequest(c.ctx, w, http.MethodPost, fmt.Sprintf("%s/history/download", c.baseURL), bytes.NewBuffer(reqBody), contentTypeJSON)
+}
@@ -0,0 +1,158 @@
+package elevenlabs_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/haguro/elevenlabs-go"
+)
+
+func makeZip(t *testing.T, files ...string) []byte {
+	t.Helper()
+	b := bytes.Buffer{}
+	zw := zip.NewWriter(&b)
+	for i := 0; i < len(files); i += 2 {
+		f, err := zw.Create(files[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(files[i+1]))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+type entrySummary struct {
+	ID, Filename, Data string
+}
+
+func summarizeEntries(t *testing.T, audio elevenlabs.HistoryAudio) []entrySummary {
+	t.Helper()
+	var s []entrySummary
+	for _, e := range audio.Entries {
+		rc, err := e.Open()
+		if err != nil {
+			t.Fatalf("Failed to open entry %q: %s", e.Filename, err)
+		}
+		b, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatalf("Failed to read entry %q: %s", e.Filename, err)
+		}
+		s = append(s, entrySummary{e.HistoryItemID, e.Filename, string(b)})
+	}
+	return s
+}
+
+func TestParseHistoryAudio(t *testing.T) {
+	testCases := []struct {
+		name       string
+		data       []byte
+		ids        []string
+		expZip     bool
+		expEntries []entrySummary
+	}{
+		{
+			name:       "Single audio file",
+			data:       []byte("mp3-data"),
+			ids:        []string{"h1"},
+			expEntries: []entrySummary{{"h1", "", "mp3-data"}},
+		},
+		{
+			name:       "Zip matched by name",
+			data:       makeZip(t, "dir/Voice_h2_text.mp3", "two", "Voice_h1_text.mp3", "one"),
+			ids:        []string{"h1", "h2"},
+			expZip:     true,
+			expEntries: []entrySummary{{"h2", "dir/Voice_h2_text.mp3", "two"}, {"h1", "Voice_h1_text.mp3", "one"}},
+		},
+		{
+			name:       "Zip without IDs in names",
+			data:       makeZip(t, "a.mp3", "one", "b.mp3", "two"),
+			ids:        []string{"h1", "h2"},
+			expZip:     true,
+			expEntries: []entrySummary{{"", "a.mp3", "one"}, {"", "b.mp3", "two"}},
+		},
+		{
+			name:       "Zip with unmatched entries",
+			data:       makeZip(t, "a.mp3", "one"),
+			ids:        []string{"h1", "h2"},
+			expZip:     true,
+			expEntries: []entrySummary{{"", "a.mp3", "one"}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			audio, err := elevenlabs.ParseHistoryAudio(tc.data, tc.ids)
+			if err != nil {
+				t.Fatalf("Expected no errors, got %q", err)
+			}
+			if audio.Zip != tc.expZip {
+				t.Errorf("Expected Zip to be %t, got %t", tc.expZip, audio.Zip)
+			}
+			if got := summarizeEntries(t, audio); !reflect.DeepEqual(got, tc.expEntries) {
+				t.Errorf("Expected entries %+v, got %+v", tc.expEntries, got)
+			}
+			// Entries can be opened again after being read.
+			if got := summarizeEntries(t, audio); !reflect.DeepEqual(got, tc.expEntries) {
+				t.Errorf("Expected entries %+v when read again, got %+v", tc.expEntries, got)
+			}
+		})
+	}
+
+	if _, err := elevenlabs.ParseHistoryAudio([]byte("PK\x03\x04garbage"), []string{"h1", "h2"}); err == nil {
+		t.Error("Expected an error for a malformed zip file")
+	}
+}
+
+func TestDownloadHistoryAudioFiles(t *testing.T) {
+	data := makeZip(t, "x_h1.mp3", "one", "x_h2.mp3", "two")
+	server := testServer(t, testServerConfig{
+		expectedMethod:      http.MethodPost,
+		expectedContentType: contentTypeJSON,
+		statusCode:          http.StatusOK,
+		responseBody:        data,
+	})
+	defer server.Close()
+	client := elevenlabs.NewMockClient(context.Background(), server.URL, mockAPIKey, mockTimeout)
+	dlReq := elevenlabs.DownloadHistoryRequest{HistoryItemIds: []string{"h1", "h2"}}
+
+	audio, err := client.DownloadHistoryAudioFiles(dlReq)
+	if err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+	entry, ok := audio.Entry("h2")
+	if !ok || entry.Filename != "x_h2.mp3" {
+		t.Errorf("Expected entry x_h2.mp3 for h2, got %+v", entry)
+	}
+
+	path := filepath.Join(t.TempDir(), "download.zip")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := client.DownloadHistoryAudioStream(f, dlReq); err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+	audio, err = elevenlabs.OpenHistoryAudio(f, info.Size(), dlReq.HistoryItemIds)
+	if err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+	exp := []entrySummary{{"h1", "x_h1.mp3", "one"}, {"h2", "x_h2.mp3", "two"}}
+	if got := summarizeEntries(t, audio); !reflect.DeepEqual(got, exp) {
+		t.Errorf("Expected entries %+v, got %+v", exp, got)
+	}
+}
@@ -0,0 +1,134 @@
+package elevenlabs
+
+import (
+	"context"
+)
+
+// Page represents a single page of items returned by a paginated endpoint.
+type Page[T any] struct {
+	Items []T
+	// NextCursor is the cursor used to retrieve the page following this one.
+	NextCursor string
+	HasMore    bool
+}
+
+// PageFunc represents functions that retrieve the page of items that follows a given cursor. An empty cursor
+// refers to the first page.
+type PageFunc[T any] func(ctx context.Context, cursor string) (Page[T], error)
+
+// Iterator walks through all the items of a paginated endpoint, retrieving pages as they are needed.
+//
+// A typical use is:
+//
+//	it := client.HistoryIterator(elevenlabs.PageSize(100))
+//	for it.Next(ctx) {
+//		item := it.Item()
+//		// ...
+//	}
+//	if err := it.Err(); err != nil {
+//		// ...
+//	}
+//
+// Iteration can be stopped at any point, and resumed later by starting a new iterator from the value
+// returned by Cursor.
+//
+// The NewIterator function should be used when instantiating a new Iterator.
+type Iterator[T any] struct {
+	fetch       PageFunc[T]
+	cursorOf    func(T) string
+	items       []T
+	idx         int
+	item        T
+	pageCursor  string
+	nextCursor  string
+	hasMore     bool
+	itemCursor  string
+	hasItem     bool
+	err         error
+	initialized bool
+}
+
+// NewIterator creates and returns a new Iterator.
+//
+// It takes a PageFunc argument used to retrieve the pages, a string argument that represents the cursor to
+// start from (an empty string to start from the first page), and an optional function that returns the
+// cursor that refers to the position right after a given item. When that function is nil, Cursor can only
+// resume iteration from page boundaries.
+func NewIterator[T any](fetch PageFunc[T], startCursor string, cursorOf func(T) string) *Iterator[T] {
+	return &Iterator[T]{fetch: fetch, cursorOf: cursorOf, nextCursor: startCursor, pageCursor: startCursor, hasMore: true}
+}
+
+// Next advances the iterator to the next item, retrieving the next page if needed. The item is then available
+// through the Item method.
+//
+// It returns false when there are no more items, the context is done or an error occurred. Err should be
+// checked afterwards to distinguish between the cases.
+func (it *Iterator[T]) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+	for it.idx >= len(it.items) {
+		if !it.hasMore {
+			return false
+		}
+		if err := ctx.Err(); err != nil {
+			it.err = err
+			return false
+		}
+		page, err := it.fetch(ctx, it.nextCursor)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		// Guard against endpoints that keep reporting more items without moving the cursor forward.
+		if it.initialized && page.HasMore && page.NextCursor == it.nextCursor {
+			page.HasMore = false
+		}
+		it.initialized = true
+		it.items, it.idx = page.Items, 0
+		it.pageCursor, it.nextCursor, it.hasMore = it.nextCursor, page.NextCursor, page.HasMore
+	}
+	it.item = it.items[it.idx]
+	it.idx++
+	it.hasItem = true
+	if it.cursorOf != nil {
+		it.itemCursor = it.cursorOf(it.item)
+	}
+	return true
+}
+
+// Item returns the current item. It should only be called after a call to Next returned true.
+func (it *Iterator[T]) Item() T {
+	return it.item
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// Cursor returns a cursor that can be passed to NewIterator (or StartAfter for history) to resume iteration
+// right after the current item.
+//
+// If the iterator was created without an item cursor function, the returned cursor refers to the start of
+// the current page, unless all of its items were consumed, so resuming may yield some items again.
+func (it *Iterator[T]) Cursor() string {
+	if it.cursorOf != nil && it.hasItem {
+		return it.itemCursor
+	}
+	if it.initialized && it.idx >= len(it.items) {
+		return it.nextCursor
+	}
+	return it.pageCursor
+}
+
+// Collect retrieves all the remaining items of the iterator.
+//
+// It returns the items retrieved until the end of the iteration or the first error, and that error.
+func (it *Iterator[T]) Collect(ctx context.Context) ([]T, error) {
+	var items []T
+	for it.Next(ctx) {
+		items = append(items, it.Item())
+	}
+	return items, it.Err()
+}
@@ -0,0 +1,33 @@
+//go:build go1.23
+
+package elevenlabs
+
+import (
+	"context"
+	"iter"
+)
+
+// All returns an iterator over the remaining items that can be used with a range loop:
+//
+//	for item, err := range client.HistoryIterator().All(ctx) {
+//		if err != nil {
+//			// ...
+//		}
+//		// ...
+//	}
+//
+// If an error occurs, it is yielded once with the zero value of T as the last pair. Breaking out of the loop
+// stops the iteration without retrieving further pages.
+func (it *Iterator[T]) All(ctx context.Context) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for it.Next(ctx) {
+			if !yield(it.Item(), nil) {
+				return
+			}
+		}
+		if err := it.Err(); err != nil {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+//go:build go1.23
+
+package elevenlabs_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/haguro/elevenlabs-go"
+)
+
+func TestIteratorAll(t *testing.T) {
+	calls := 0
+	it := elevenlabs.NewIterator(numberPages(10, 3, &calls), "", strconv.Itoa)
+	var got []int
+	for n, err := range it.All(context.Background()) {
+		if err != nil {
+			t.Fatalf("Expected no errors, got %q", err)
+		}
+		got = append(got, n)
+		if n == 4 {
+			break
+		}
+	}
+	if exp := []int{1, 2, 3, 4}; !reflect.DeepEqual(got, exp) {
+		t.Errorf("Expected items %v, got %v", exp, got)
+	}
+	if calls != 2 {
+		t.Errorf("Expected breaking out of the loop to stop fetching pages, got %d calls", calls)
+	}
+
+	fetchErr := errors.New("fetch failed")
+	failing := elevenlabs.NewIterator(func(ctx context.Context, cursor string) (elevenlabs.Page[int], error) {
+		return elevenlabs.Page[int]{}, fetchErr
+	}, "", nil)
+	for _, err := range failing.All(context.Background()) {
+		if !errors.Is(err, fetchErr) {
+			t.Errorf("Expected error %q, got %v", fetchErr, err)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package elevenlabs_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/haguro/elevenlabs-go"
+)
+
+// numberPages returns a PageFunc serving the numbers 1 to n in pages of a given size, using the last
+// number of a page as the cursor.
+func numberPages(n, size int, calls *int) elevenlabs.PageFunc[int] {
+	return func(ctx context.Context, cursor string) (elevenlabs.Page[int], error) {
+		*calls++
+		start := 0
+		if cursor != "" {
+			var err error
+			if start, err = strconv.Atoi(cursor); err != nil {
+				return elevenlabs.Page[int]{}, err
+			}
+		}
+		var page elevenlabs.Page[int]
+		for i := start + 1; i <= n && i <= start+size; i++ {
+			page.Items = append(page.Items, i)
+		}
+		if len(page.Items) > 0 {
+			page.NextCursor = strconv.Itoa(page.Items[len(page.Items)-1])
+		}
+		page.HasMore = start+size < n
+		return page, nil
+	}
+}
+
+func TestIterator(t *testing.T) {
+	ctx := context.Background()
+	calls := 0
+	it := elevenlabs.NewIterator(numberPages(10, 3, &calls), "", strconv.Itoa)
+	items, err := it.Collect(ctx)
+	if err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+	if exp := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}; !reflect.DeepEqual(items, exp) {
+		t.Errorf("Expected items %v, got %v", exp, items)
+	}
+	if calls != 4 {
+		t.Errorf("Expected 4 pages to be fetched, got %d", calls)
+	}
+	if it.Next(ctx) {
+		t.Error("Expected Next to return false after the last item")
+	}
+}
+
+func TestIteratorResume(t *testing.T) {
+	ctx := context.Background()
+	testCases := []struct {
+		name     string
+		cursorOf func(int) string
+		expNext  []int
+	}{
+		{"with item cursors", strconv.Itoa, []int{6, 7}},
+		{"with page cursors", nil, []int{4, 5}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			calls := 0
+			it := elevenlabs.NewIterator(numberPages(10, 3, &calls), "", tc.cursorOf)
+			for it.Next(ctx) {
+				if it.Item() == 5 {
+					break
+				}
+			}
+			cursor := it.Cursor()
+
+			resumed := elevenlabs.NewIterator(numberPages(10, 3, &calls), cursor, tc.cursorOf)
+			var got []int
+			for len(got) < 2 && resumed.Next(ctx) {
+				got = append(got, resumed.Item())
+			}
+			if !reflect.DeepEqual(got, tc.expNext) {
+				t.Errorf("Expected resumed iterator to yield %v, got %v", tc.expNext, got)
+			}
+		})
+	}
+}
+
+func TestIteratorErrors(t *testing.T) {
+	fetchErr := errors.New("fetch failed")
+	pages := 0
+	it := elevenlabs.NewIterator(func(ctx context.Context, cursor string) (elevenlabs.Page[string], error) {
+		pages++
+		if pages > 1 {
+			return elevenlabs.Page[string]{}, fetchErr
+		}
+		return elevenlabs.Page[string]{Items: []string{"a"}, NextCursor: "a", HasMore: true}, nil
+	}, "", nil)
+	items, err := it.Collect(context.Background())
+	if !errors.Is(err, fetchErr) {
+		t.Errorf("Expected error %q, got %v", fetchErr, err)
+	}
+	if !reflect.DeepEqual(items, []string{"a"}) {
+		t.Errorf("Expected the items retrieved before the error, got %v", items)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	calls := 0
+	it2 := elevenlabs.NewIterator(numberPages(10, 3, &calls), "", nil)
+	if it2.Next(ctx) || !errors.Is(it2.Err(), context.Canceled) || calls != 0 {
+		t.Errorf("Expected a cancelled context to stop the iterator before fetching, got %v after %d calls", it2.Err(), calls)
+	}
+
+	stuck := elevenlabs.NewIterator(func(ctx context.Context, cursor string) (elevenlabs.Page[int], error) {
+		return elevenlabs.Page[int]{Items: []int{1}, NextCursor: "same", HasMore: true}, nil
+	}, "", nil)
+	if items, _ := stuck.Collect(context.Background()); len(items) != 2 {
+		t.Errorf("Expected iteration to stop when the cursor does not move, got %d items", len(items))
+	}
+}
+
+func TestHistoryIterator(t *testing.T) {
+	var gotQueries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("xi-api-key") != mockAPIKey {
+			t.Errorf("Server: expected API Key %q, got %q", mockAPIKey, r.Header.Get("xi-api-key"))
+		}
+		gotQueries = append(gotQueries, r.URL.RawQuery)
+		switch r.URL.Query().Get("start_after_history_item_id") {
+		case "":
+			fmt.Fprint(w, `{"history":[{"history_item_id":"h1"},{"history_item_id":"h2"}],"last_history_item_id":"h2","has_more":true}`)
+		case "h2":
+			fmt.Fprint(w, `{"history":[{"history_item_id":"h3"}],"last_history_item_id":"h3","has_more":false}`)
+		default:
+			t.Errorf("Server: unexpected query %q", r.URL.RawQuery)
+		}
+	}))
+	defer server.Close()
+	client := elevenlabs.NewMockClient(context.Background(), server.URL, mockAPIKey, mockTimeout)
+
+	it := client.HistoryIterator(elevenlabs.PageSize(2))
+	var ids []string
+	for it.Next(context.Background()) {
+		ids = append(ids, it.Item().HistoryItemId)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+	if exp := []string{"h1", "h2", "h3"}; !reflect.DeepEqual(ids, exp) {
+		t.Errorf("Expected history items %v, got %v", exp, ids)
+	}
+	if exp := []string{"page_size=2", "page_size=2&start_after_history_item_id=h2"}; !reflect.DeepEqual(gotQueries, exp) {
+		t.Errorf("Expected queries %v, got %v", exp, gotQueries)
+	}
+	if it.Cursor() != "h3" {
+		t.Errorf("Expected cursor %q, got %q", "h3", it.Cursor())
+	}
+
+	gotQueries = nil
+	resumed := client.HistoryIterator(elevenlabs.StartAfter("h2"), elevenlabs.PageSize(2))
+	items, err := resumed.Collect(context.Background())
+	if err != nil || len(items) != 1 || items[0].HistoryItemId != "h3" {
+		t.Errorf("Expected resumed iterator to return only h3, got %+v (error: %v)", items, err)
+	}
+	if exp := []string{"page_size=2&start_after_history_item_id=h2"}; !reflect.DeepEqual(gotQueries, exp) {
+		t.Errorf("Expected queries %v, got %v", exp, gotQueries)
+	}
+}
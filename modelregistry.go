@@ -0,0 +1,181 @@
+package elevenlabs
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultModelID is the model used by the API when the ModelID of a TextToSpeechRequest is empty.
+	DefaultModelID = "eleven_monolingual_v1"
+	// DefaultModelCacheTTL is the default time the models cached by a Client are used before being retrieved
+	// again.
+	DefaultModelCacheTTL = time.Hour
+)
+
+// SupportsLanguage reports whether the model supports a language, given its ID such as "en" or "pt-br".
+func (m Model) SupportsLanguage(languageId string) bool {
+	for _, l := range m.Languages {
+		if strings.EqualFold(l.LanguageId, languageId) {
+			return true
+		}
+	}
+	return false
+}
+
+// MaxCharacters returns the maximum number of characters of a single request to the model, which depends on
+// whether the user has a paid subscription. A value of 0 means the model has no known limit.
+func (m Model) MaxCharacters(subscribed bool) int {
+	if subscribed {
+		return m.MaxCharactersRequestSubscribedUser
+	}
+	return m.MaxCharactersRequestFreeUser
+}
+
+// ValidateTextToSpeech checks that a text to speech request can be sent to the model: the model supports text
+// to speech and the settings and languages used, the options of the request are valid, and the text is within
+// the model's character limit.
+//
+// It takes a TextToSpeechRequest argument, a string argument that represents the ID of the language of the
+// text, or an empty string to only check the LanguageCode of the request, and a bool argument that is true if
+// the user has a paid subscription.
+//
+// It returns nil if the request is supported or a *TextToSpeechValidationError listing all the problems.
+func (m Model) ValidateTextToSpeech(ttsReq TextToSpeechRequest, languageId string, subscribed bool) error {
+	return textToSpeechValidationError(m.textToSpeechProblems(ttsReq, languageId, m.MaxCharacters(subscribed)))
+}
+
+func (m Model) textToSpeechProblems(ttsReq TextToSpeechRequest, languageId string, maxChars int) []string {
+	var problems []string
+	if !m.CanDoTextToSpeech {
+		problems = append(problems, fmt.Sprintf("model %q does not support text to speech", m.ModelId))
+	}
+	if s := ttsReq.VoiceSettings; s != nil {
+		if s.Style != nil && *s.Style != 0 && !m.CanUseStyle {
+			problems = append(problems, fmt.Sprintf("model %q does not support style", m.ModelId))
+		}
+		if s.SpeakerBoost != nil && *s.SpeakerBoost && !m.CanUseSpeakerBoost {
+			problems = append(problems, fmt.Sprintf("model %q does not support speaker boost", m.ModelId))
+		}
+	}
+	for _, lang := range []string{languageId, ttsReq.LanguageCode} {
+		if lang != "" && !m.SupportsLanguage(lang) {
+			problems = append(problems, fmt.Sprintf("model %q does not support language %q", m.ModelId, lang))
+			break
+		}
+	}
+	switch ttsReq.ApplyTextNormalization {
+	case "", TextNormalizationAuto, TextNormalizationOn, TextNormalizationOff:
+	default:
+		problems = append(problems, fmt.Sprintf("unknown text normalization %q", ttsReq.ApplyTextNormalization))
+	}
+	if n := len(ttsReq.PreviousRequestIds); n > MaxRelatedRequestIds {
+		problems = append(problems, fmt.Sprintf("%d previous request IDs exceed the maximum of %d", n, MaxRelatedRequestIds))
+	}
+	if n := len(ttsReq.NextRequestIds); n > MaxRelatedRequestIds {
+		problems = append(problems, fmt.Sprintf("%d next request IDs exceed the maximum of %d", n, MaxRelatedRequestIds))
+	}
+	n := utf8.RuneCountInString(ttsReq.Text)
+	switch {
+	case strings.TrimSpace(ttsReq.Text) == "":
+		problems = append(problems, "text must not be empty")
+	case maxChars > 0 && n > maxChars:
+		problems = append(problems, fmt.Sprintf("text of %d characters exceeds the limit of %d of model %q", n, maxChars, m.ModelId))
+	}
+	return problems
+}
+
+func textToSpeechValidationError(problems []string) error {
+	if len(problems) > 0 {
+		return &TextToSpeechValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// SetModelCacheTTL sets the time the models retrieved by the model lookup methods are cached for.
+//
+// It takes a time.Duration argument that represents the new TTL. A value of 0 or less disables caching.
+func (c *Client) SetModelCacheTTL(ttl time.Duration) {
+	c.models.setTTL(ttl)
+}
+
+// CachedModels returns the models of GetModels, retrieving them only if they are not cached or the cached
+// models are older than the cache TTL (see SetModelCacheTTL).
+//
+// It returns a slice of Model objects, which must not be modified, or an error.
+func (c *Client) CachedModels() ([]Model, error) {
+	return c.models.get(c.GetModels)
+}
+
+// FindModel returns a model given its ID, using the cached models (see CachedModels).
+//
+// It takes a string argument that represents the ID of the model.
+//
+// It returns the Model, or an error wrapping ErrModelNotFound if no model has that ID.
+func (c *Client) FindModel(modelId string) (Model, error) {
+	models, err := c.CachedModels()
+	if err != nil {
+		return Model{}, err
+	}
+	for _, m := range models {
+		if m.ModelId == modelId {
+			return m, nil
+		}
+	}
+	return Model{}, fmt.Errorf("%w: %q", ErrModelNotFound, modelId)
+}
+
+// ValidateTextToSpeech checks a text to speech request against its model before it is sent (see
+// Model.ValidateTextToSpeech) and checks that the model is one of the voice's HighQualityBaseModelIds. The
+// models, voices and subscription are looked up in the caches of the client (see CachedModels, CachedVoices and
+// SetSubscriptionCacheTTL), and the subscription is only needed when the text exceeds the character limit for free
+// users.
+//
+// It takes a string argument that represents the ID of the voice, a TextToSpeechRequest argument and a string
+// argument that represents the ID of the language of the text, or an empty string to only check the
+// LanguageCode of the request.
+//
+// It returns nil if the request is supported, a *TextToSpeechValidationError listing all the problems, an
+// error wrapping ErrModelNotFound if the model does not exist, or an error if the models, voices or
+// subscription could not be retrieved.
+func (c *Client) ValidateTextToSpeech(voiceId string, ttsReq TextToSpeechRequest, languageId string) error {
+	modelId := ttsReq.ModelID
+	if modelId == "" {
+		modelId = DefaultModelID
+	}
+	model, err := c.FindModel(modelId)
+	if err != nil {
+		return err
+	}
+
+	maxChars := model.MaxCharacters(false)
+	if maxChars > 0 && utf8.RuneCountInString(ttsReq.Text) > maxChars {
+		sub, err := c.subscription.get(c.GetSubscription)
+		if err != nil {
+			return err
+		}
+		maxChars = model.MaxCharacters(sub.Tier != TierFree)
+	}
+	problems := model.textToSpeechProblems(ttsReq, languageId, maxChars)
+
+	voices, err := c.CachedVoices()
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, v := range voices {
+		if v.VoiceId == voiceId {
+			found = true
+			if !(VoiceQuery{ModelID: modelId}).Match(v) {
+				problems = append(problems, fmt.Sprintf("voice %q is not compatible with model %q", v.Name, modelId))
+			}
+			break
+		}
+	}
+	if !found {
+		problems = append(problems, fmt.Sprintf("voice %q not found", voiceId))
+	}
+	return textToSpeechValidationError(problems)
+}
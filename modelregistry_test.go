@@ -0,0 +1,130 @@
+package elevenlabs_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/haguro/elevenlabs-go"
+)
+
+const registryModels = `[
+	{"model_id":"eleven_monolingual_v1","can_do_text_to_speech":true,"can_use_speaker_boost":true,"languages":[{"language_id":"en","name":"English"}],
+		"max_characters_request_free_user":10,"max_characters_request_subscribed_user":20},
+	{"model_id":"eleven_multilingual_v2","can_do_text_to_speech":true,"can_use_style":true,"can_use_speaker_boost":true,
+		"languages":[{"language_id":"en","name":"English"},{"language_id":"de","name":"German"}]},
+	{"model_id":"eleven_english_sts_v2","can_do_voice_conversion":true}
+]`
+
+const registryVoices = `{"voices":[
+	{"voice_id":"v1","name":"Rachel","high_quality_base_model_ids":["eleven_monolingual_v1","eleven_multilingual_v2"]},
+	{"voice_id":"v2","name":"George","high_quality_base_model_ids":["eleven_monolingual_v1"]}
+]}`
+
+// registryRoutes serves fixed lists of models and voices and a subscription of a given tier.
+func registryRoutes(tier string) map[string]http.HandlerFunc {
+	return map[string]http.HandlerFunc{
+		"GET /models": func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, registryModels)
+		},
+		"GET /voices": func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, registryVoices)
+		},
+		"GET /user/subscription": func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `{"tier":%q}`, tier)
+		},
+	}
+}
+
+func TestValidateTextToSpeech(t *testing.T) {
+	tests := []struct {
+		name        string
+		tier        string
+		voiceId     string
+		ttsReq      elevenlabs.TextToSpeechRequest
+		languageId  string
+		expectedErr []string
+	}{
+		{"Valid", "free", "v1", elevenlabs.TextToSpeechRequest{Text: "Hello"}, "en", nil},
+		{"ValidWithStyle", "free", "v1", elevenlabs.TextToSpeechRequest{Text: "Hallo", ModelID: "eleven_multilingual_v2",
+			VoiceSettings: &elevenlabs.VoiceSettings{Style: elevenlabs.Float32(0.5), SpeakerBoost: elevenlabs.Bool(true)}}, "de", nil},
+		{"SubscribedLimit", "creator", "v1", elevenlabs.TextToSpeechRequest{Text: "Hello there!"}, "", nil},
+		{"FreeLimit", "free", "v1", elevenlabs.TextToSpeechRequest{Text: "Hello there!"}, "", []string{
+			`text of 12 characters exceeds the limit of 10 of model "eleven_monolingual_v1"`}},
+		{"Unsupported", "free", "v2", elevenlabs.TextToSpeechRequest{Text: " ", ModelID: "eleven_english_sts_v2",
+			VoiceSettings: &elevenlabs.VoiceSettings{Style: elevenlabs.Float32(0.5), SpeakerBoost: elevenlabs.Bool(true)}}, "de", []string{
+			`model "eleven_english_sts_v2" does not support text to speech`,
+			`model "eleven_english_sts_v2" does not support style`,
+			`model "eleven_english_sts_v2" does not support speaker boost`,
+			`model "eleven_english_sts_v2" does not support language "de"`,
+			"text must not be empty",
+			`voice "George" is not compatible with model "eleven_english_sts_v2"`}},
+		{"RequestOptions", "free", "v1", elevenlabs.TextToSpeechRequest{Text: "Hallo", ModelID: "eleven_multilingual_v2", LanguageCode: "de",
+			Seed: elevenlabs.Uint32(0), ApplyTextNormalization: elevenlabs.TextNormalizationOff, PreviousRequestIds: []string{"r1", "r2", "r3"}}, "", nil},
+		{"InvalidRequestOptions", "free", "v1", elevenlabs.TextToSpeechRequest{Text: "Hello", LanguageCode: "de",
+			ApplyTextNormalization: "always", NextRequestIds: []string{"r1", "r2", "r3", "r4"}}, "", []string{
+			`model "eleven_monolingual_v1" does not support language "de"`,
+			`unknown text normalization "always"`,
+			"4 next request IDs exceed the maximum of 3"}},
+		{"UnknownVoice", "free", "v9", elevenlabs.TextToSpeechRequest{Text: "Hello"}, "", []string{`voice "v9" not found`}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			log := &requestLog{}
+			server := testServer(t, testServerConfig{routes: registryRoutes(tc.tier), log: log})
+			defer server.Close()
+			client := elevenlabs.NewMockClient(context.Background(), server.URL, mockAPIKey, mockTimeout)
+
+			client.ValidateTextToSpeech(tc.voiceId, tc.ttsReq, tc.languageId)
+			err := client.ValidateTextToSpeech(tc.voiceId, tc.ttsReq, tc.languageId)
+			if gets := log.count("GET /user/subscription"); gets > 1 {
+				t.Errorf("Expected the subscription to be retrieved at most once, got %d requests", gets)
+			}
+			if tc.expectedErr == nil {
+				if err != nil {
+					t.Errorf("Expected no errors, got %q", err)
+				}
+				return
+			}
+			var valErr *elevenlabs.TextToSpeechValidationError
+			if !errors.As(err, &valErr) {
+				t.Fatalf("Expected a TextToSpeechValidationError, got %v", err)
+			}
+			if !reflect.DeepEqual(valErr.Problems, tc.expectedErr) {
+				t.Errorf("Expected problems:\n%s\ngot:\n%s", strings.Join(tc.expectedErr, "\n"), strings.Join(valErr.Problems, "\n"))
+			}
+		})
+	}
+}
+
+func TestFindModel(t *testing.T) {
+	log := &requestLog{}
+	server := testServer(t, testServerConfig{routes: registryRoutes(""), log: log})
+	defer server.Close()
+	client := elevenlabs.NewMockClient(context.Background(), server.URL, mockAPIKey, mockTimeout)
+
+	m, err := client.FindModel("eleven_multilingual_v2")
+	if err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+	if !m.SupportsLanguage("DE") || m.SupportsLanguage("fr") {
+		t.Errorf("Unexpected languages for model %q: %v", m.ModelId, m.Languages)
+	}
+	if _, err := client.FindModel("eleven_unknown"); !errors.Is(err, elevenlabs.ErrModelNotFound) {
+		t.Errorf("Expected error %v, got %v", elevenlabs.ErrModelNotFound, err)
+	}
+	if gets := log.count("GET /models"); gets != 1 {
+		t.Errorf("Expected the models to be retrieved once, got %d", gets)
+	}
+	client.SetModelCacheTTL(0)
+	if _, err := client.CachedModels(); err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+	if gets := log.count("GET /models"); gets != 2 {
+		t.Errorf("Expected the models to be retrieved again with caching disabled, got %d requests", gets)
+	}
+}
@@ -1,13 +1,17 @@
 package elevenlabs
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/fs"
+	"mime"
 	"mime/multipart"
+	"net/textproto"
 	"os"
+	"path"
 	"path/filepath"
+	"strings"
 )
 
 type Language struct {
@@ -36,8 +40,39 @@ type TextToSpeechRequest struct {
 	Text          string         `json:"text"`
 	ModelID       string         `json:"model_id,omitempty"`
 	VoiceSettings *VoiceSettings `json:"voice_settings,omitempty"`
+	// LanguageCode forces the language of the generated speech (ISO 639-1, e.g. "de"). It must be one of the
+	// model's Languages.
+	LanguageCode string `json:"language_code,omitempty"`
+	// Seed makes the generated speech deterministic on a best effort basis: requests with the same seed and
+	// parameters should return the same audio.
+	Seed *uint32 `json:"seed,omitempty"`
+	// PreviousText and NextText are the text that comes before and after the text of the request, used to
+	// improve the continuity of speech split across requests.
+	PreviousText string `json:"previous_text,omitempty"`
+	NextText     string `json:"next_text,omitempty"`
+	// PreviousRequestIds and NextRequestIds are the IDs of up to MaxRelatedRequestIds requests generated before
+	// and after this one, used like PreviousText and NextText. PreviousText is ignored when PreviousRequestIds
+	// is set, and NextText is ignored when NextRequestIds is set.
+	PreviousRequestIds []string `json:"previous_request_ids,omitempty"`
+	NextRequestIds     []string `json:"next_request_ids,omitempty"`
+	// ApplyTextNormalization controls the normalization of numbers, dates and the like in the text. It is one
+	// of TextNormalizationAuto, TextNormalizationOn or TextNormalizationOff.
+	ApplyTextNormalization string `json:"apply_text_normalization,omitempty"`
+	// UsePVCAsIVC uses the instant voice clone of a professional voice clone, which may have lower latency.
+	UsePVCAsIVC bool `json:"use_pvc_as_ivc,omitempty"`
 }
 
+// Values of TextToSpeechRequest.ApplyTextNormalization.
+const (
+	TextNormalizationAuto = "auto"
+	TextNormalizationOn   = "on"
+	TextNormalizationOff  = "off"
+)
+
+// MaxRelatedRequestIds is the maximum number of IDs in the PreviousRequestIds and NextRequestIds of a
+// TextToSpeechRequest.
+const MaxRelatedRequestIds = 3
+
 type GetVoicesResponse struct {
 	Voices []Voice `json:"voices"`
 }
@@ -61,11 +96,25 @@ type Voice struct {
 	VoiceId                 string            `json:"voice_id"`
 }
 
+// Float32 returns a pointer to a float32 value, for use with optional request fields such as VoiceSettings.Style.
+func Float32(v float32) *float32 { return &v }
+
+// Bool returns a pointer to a bool value, for use with optional request fields such as
+// VoiceSettings.SpeakerBoost.
+func Bool(v bool) *bool { return &v }
+
+// Uint32 returns a pointer to a uint32 value, for use with optional request fields such as
+// TextToSpeechRequest.Seed.
+func Uint32(v uint32) *uint32 { return &v }
+
+// VoiceSettings represents the settings of a voice. Style and SpeakerBoost are pointers so that explicit zero
+// values can be sent; a nil value is omitted and the API default is used. Float32 and Bool return pointers to
+// literal values.
 type VoiceSettings struct {
-	SimilarityBoost float32 `json:"similarity_boost"`
-	Stability       float32 `json:"stability"`
-	Style           float32 `json:"style,omitempty"`
-	SpeakerBoost    bool    `json:"use_speaker_boost,omitempty"`
+	SimilarityBoost float32  `json:"similarity_boost"`
+	Stability       float32  `json:"stability"`
+	Style           *float32 `json:"style,omitempty"`
+	SpeakerBoost    *bool    `json:"use_speaker_boost,omitempty"`
 }
 
 type VoiceSharing struct {
@@ -148,6 +197,20 @@ type DownloadHistoryRequest struct {
 	HistoryItemIds []string `json:"history_item_ids"`
 }
 
+type HistoryShareLinkResponse struct {
+	ShareLinkId string `json:"share_link_id"`
+}
+
+type HistoryFeedbackRequest struct {
+	ThumbsUp        bool   `json:"thumbs_up"`
+	Feedback        string `json:"feedback"`
+	Emotions        bool   `json:"emotions"`
+	InaccurateClone bool   `json:"inaccurate_clone"`
+	Glitches        bool   `json:"glitches"`
+	AudioQuality    bool   `json:"audio_quality"`
+	Other           bool   `json:"other"`
+}
+
 type GetHistoryResponse struct {
 	History           []HistoryItem `json:"history"`
 	LastHistoryItemId string        `json:"last_history_item_id"`
@@ -218,20 +281,110 @@ type User struct {
 	CanUseDelayedPaymentMethods bool         `json:"can_use_delayed_payment_methods"`
 }
 
+// VoiceFile represents an audio sample of a voice that is read from an io.Reader rather than a file path.
+type VoiceFile struct {
+	// Name is the file name of the sample, e.g. "sample1.mp3".
+	Name string
+	// MimeType is the MIME type of the sample. If empty, it is derived from the extension of Name.
+	MimeType string
+	// Reader reads the sample's audio data. It is read when the request is sent and is not closed.
+	Reader io.Reader
+}
+
 type AddEditVoiceRequest struct {
-	Name        string
-	FilePaths   []string
+	Name string
+	// FilePaths are the paths of the sample files to upload, opened from FS if it is set or from the local
+	// file system otherwise.
+	FilePaths []string
+	// Files are samples to upload in addition to those of FilePaths.
+	Files       []VoiceFile
+	FS          fs.FS
 	Description string
 	Labels      map[string]string
 }
 
-func (r *AddEditVoiceRequest) buildRequestBody() (*bytes.Buffer, string, error) {
-	var b bytes.Buffer
-	w := multipart.NewWriter(&b)
-	buildFailed := func(err error) (*bytes.Buffer, string, error) {
+var sampleMimeTypes = map[string]string{
+	".mp3":  "audio/mpeg",
+	".mpeg": "audio/mpeg",
+	".wav":  "audio/wav",
+	".m4a":  "audio/mp4",
+	".ogg":  "audio/ogg",
+	".oga":  "audio/ogg",
+	".opus": "audio/ogg",
+	".flac": "audio/flac",
+	".webm": "audio/webm",
+	".aac":  "audio/aac",
+}
+
+// sampleMimeType returns the MIME type of a sample file based on its name.
+func sampleMimeType(name string) string {
+	ext := strings.ToLower(path.Ext(name))
+	if t, ok := sampleMimeTypes[ext]; ok {
+		return t
+	}
+	if t := mime.TypeByExtension(ext); t != "" {
+		return t
+	}
+	return "application/octet-stream"
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// buildRequestBody returns a reader that streams the multipart request body through a pipe, so that samples
+// are never buffered in memory as a whole. Sample files are opened before returning so that errors such as
+// missing files are reported before any request is made.
+func (r *AddEditVoiceRequest) buildRequestBody() (io.Reader, string, error) {
+	buildFailed := func(err error) (io.Reader, string, error) {
 		return nil, "", fmt.Errorf("failed to build request body: %w", err)
 	}
 
+	files := make([]VoiceFile, 0, len(r.FilePaths)+len(r.Files))
+	var closers []io.Closer
+	closeAll := func() {
+		for _, c := range closers {
+			c.Close()
+		}
+	}
+	for _, file := range r.FilePaths {
+		var f io.ReadCloser
+		var err error
+		if r.FS != nil {
+			f, err = r.FS.Open(file)
+		} else {
+			f, err = os.Open(file)
+		}
+		if err != nil {
+			closeAll()
+			return buildFailed(err)
+		}
+		closers = append(closers, f)
+		files = append(files, VoiceFile{Name: path.Base(filepath.ToSlash(file)), Reader: f})
+	}
+	files = append(files, r.Files...)
+
+	var labelsJson []byte
+	if len(r.Labels) > 0 {
+		var err error
+		if labelsJson, err = json.Marshal(r.Labels); err != nil {
+			closeAll()
+			return buildFailed(err)
+		}
+	}
+
+	pr, pw := io.Pipe()
+	w := multipart.NewWriter(pw)
+	go func() {
+		defer closeAll()
+		pw.CloseWithError(r.writeParts(w, labelsJson, files))
+	}()
+	return pr, w.FormDataContentType(), nil
+}
+
+func (r *AddEditVoiceRequest) writeParts(w *multipart.Writer, labelsJson []byte, files []VoiceFile) error {
+	buildFailed := func(err error) error {
+		return fmt.Errorf("failed to build request body: %w", err)
+	}
+
 	if err := w.WriteField("name", r.Name); err != nil {
 		return buildFailed(err)
 	}
@@ -240,36 +393,31 @@ func (r *AddEditVoiceRequest) buildRequestBody() (*bytes.Buffer, string, error)
 			return buildFailed(err)
 		}
 	}
-	if len(r.Labels) > 0 {
-		labelsJson, err := json.Marshal(r.Labels)
-		if err != nil {
-			return buildFailed(err)
-		}
+	if labelsJson != nil {
 		if err := w.WriteField("labels", string(labelsJson)); err != nil {
 			return buildFailed(err)
 		}
 	}
 
-	for _, file := range r.FilePaths {
-		f, err := os.Open(file)
-		if err != nil {
-			return buildFailed(err)
+	for _, file := range files {
+		mimeType := file.MimeType
+		if mimeType == "" {
+			mimeType = sampleMimeType(file.Name)
 		}
-		defer f.Close()
-
-		fw, err := w.CreateFormFile("files", filepath.Base(file))
+		h := make(textproto.MIMEHeader)
+		h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="files"; filename="%s"`, quoteEscaper.Replace(file.Name)))
+		h.Set("Content-Type", mimeType)
+		fw, err := w.CreatePart(h)
 		if err != nil {
 			return buildFailed(err)
 		}
-		if _, err = io.Copy(fw, f); err != nil {
+		if _, err = io.Copy(fw, file.Reader); err != nil {
 			return buildFailed(err)
 		}
 	}
 
-	err := w.Close()
-	if err != nil {
+	if err := w.Close(); err != nil {
 		return buildFailed(err)
 	}
-
-	return &b, w.FormDataContentType(), nil
+	return nil
 }
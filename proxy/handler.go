@@ -0,0 +1,243 @@
+// Package proxy provides an http.Handler that serves synthesized speech to clients such as web browsers
+// without exposing the Elevenlabs API key.
+//
+// The handler only synthesizes speech with allowlisted voices and models, and streams the audio to the HTTP
+// response as it is being generated.
+package proxy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"unicode/utf8"
+
+	"github.com/haguro/elevenlabs-go"
+)
+
+// DefaultMaxTextLength is the default maximum number of characters accepted by a Handler.
+const DefaultMaxTextLength = 1000
+
+// maxBodySize limits the size of JSON request bodies.
+const maxBodySize = 1 << 20
+
+// Synthesizer represents the text to speech streaming functionality of the Elevenlabs API. It is satisfied
+// by *elevenlabs.Client.
+type Synthesizer interface {
+	TextToSpeechStream(streamWriter io.Writer, voiceID string, ttsReq elevenlabs.TextToSpeechRequest, queries ...elevenlabs.QueryFunc) error
+}
+
+// Request represents a text to speech request sent to a Handler, either as a JSON body or as query string
+// parameters with the same names.
+type Request struct {
+	Text         string                 `json:"text"`
+	VoiceID      string                 `json:"voice_id,omitempty"`
+	ModelID      string                 `json:"model_id,omitempty"`
+	OutputFormat elevenlabs.AudioFormat `json:"output_format,omitempty"`
+}
+
+// Handler is an http.Handler that synthesizes speech using TextToSpeechStream and streams it to the response
+// with the Content-Type of the requested output format.
+//
+// It accepts GET requests with query string parameters, and POST requests with either a JSON body or form
+// values. Requests that omit the voice, model or output format use the handler's defaults.
+//
+// The NewHandler function should be used when instantiating a new Handler.
+type Handler struct {
+	// AllowedVoices lists the IDs of the voices that clients may request. DefaultVoiceID is always allowed.
+	AllowedVoices []string
+	// AllowedModels lists the IDs of the models that clients may request. DefaultModelID is always allowed.
+	AllowedModels []string
+	// AllowedFormats lists the output formats that clients may request. DefaultFormat is always allowed.
+	AllowedFormats []elevenlabs.AudioFormat
+	// DefaultVoiceID is used when a request does not specify a voice. If empty, requests must specify one.
+	DefaultVoiceID string
+	// DefaultModelID is used when a request does not specify a model. If empty, the API's default model is used.
+	DefaultModelID string
+	// DefaultFormat is used when a request does not specify an output format. Defaults to
+	// elevenlabs.DefaultAudioFormat.
+	DefaultFormat elevenlabs.AudioFormat
+	// MaxTextLength is the maximum number of characters accepted in a request.
+	MaxTextLength int
+	// VoiceSettings, if set, are sent with every request.
+	VoiceSettings *elevenlabs.VoiceSettings
+	// Queries are passed to TextToSpeechStream in addition to OutputFormat, e.g. LatencyOptimizations.
+	Queries []elevenlabs.QueryFunc
+
+	client Synthesizer
+}
+
+// NewHandler creates and returns a new Handler.
+//
+// It takes a Synthesizer argument (typically an *elevenlabs.Client) used to generate the speech and a string
+// argument that represents the ID of the voice used by default. The handler uses DefaultMaxTextLength and
+// elevenlabs.DefaultAudioFormat unless its fields are changed.
+func NewHandler(client Synthesizer, defaultVoiceID string) *Handler {
+	return &Handler{
+		DefaultVoiceID: defaultVoiceID,
+		DefaultFormat:  elevenlabs.DefaultAudioFormat,
+		MaxTextLength:  DefaultMaxTextLength,
+		client:         client,
+	}
+}
+
+type requestError struct {
+	status int
+	msg    string
+}
+
+func (e *requestError) Error() string {
+	return e.msg
+}
+
+func badRequest(format string, a ...interface{}) error {
+	return &requestError{status: http.StatusBadRequest, msg: fmt.Sprintf(format, a...)}
+}
+
+func forbidden(format string, a ...interface{}) error {
+	return &requestError{status: http.StatusForbidden, msg: fmt.Sprintf(format, a...)}
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
+
+func contains[T comparable](list []T, v T) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *Handler) parseRequest(w http.ResponseWriter, r *http.Request) (Request, error) {
+	var req Request
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if mediaType == "application/json" {
+			if err := json.NewDecoder(io.LimitReader(r.Body, maxBodySize)).Decode(&req); err != nil {
+				return Request{}, badRequest("invalid JSON body: %s", err)
+			}
+			return req, nil
+		}
+	default:
+		return Request{}, &requestError{status: http.StatusMethodNotAllowed, msg: "method not allowed"}
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
+	if err := r.ParseForm(); err != nil {
+		return Request{}, badRequest("invalid request: %s", err)
+	}
+	req.Text = r.Form.Get("text")
+	req.VoiceID = r.Form.Get("voice_id")
+	req.ModelID = r.Form.Get("model_id")
+	req.OutputFormat = elevenlabs.AudioFormat(r.Form.Get("output_format"))
+	return req, nil
+}
+
+// validate applies the handler's defaults to a request and checks it against the handler's allowlists.
+func (h *Handler) validate(req *Request) error {
+	if req.Text == "" {
+		return badRequest("text is required")
+	}
+	maxLen := h.MaxTextLength
+	if maxLen <= 0 {
+		maxLen = DefaultMaxTextLength
+	}
+	if n := utf8.RuneCountInString(req.Text); n > maxLen {
+		return badRequest("text is too long (%d characters, maximum is %d)", n, maxLen)
+	}
+
+	if req.VoiceID == "" {
+		req.VoiceID = h.DefaultVoiceID
+	}
+	if req.VoiceID == "" {
+		return badRequest("voice_id is required")
+	}
+	if req.VoiceID != h.DefaultVoiceID && !contains(h.AllowedVoices, req.VoiceID) {
+		return forbidden("voice %q is not allowed", req.VoiceID)
+	}
+
+	if req.ModelID == "" {
+		req.ModelID = h.DefaultModelID
+	}
+	if req.ModelID != h.DefaultModelID && !contains(h.AllowedModels, req.ModelID) {
+		return forbidden("model %q is not allowed", req.ModelID)
+	}
+
+	defaultFormat := h.DefaultFormat
+	if defaultFormat == "" {
+		defaultFormat = elevenlabs.DefaultAudioFormat
+	}
+	if req.OutputFormat == "" {
+		req.OutputFormat = defaultFormat
+	}
+	if !req.OutputFormat.Valid() {
+		return badRequest("unknown output format %q", req.OutputFormat)
+	}
+	if req.OutputFormat != defaultFormat && !contains(h.AllowedFormats, req.OutputFormat) {
+		return forbidden("output format %q is not allowed", req.OutputFormat)
+	}
+	return nil
+}
+
+// ServeHTTP handles a text to speech request.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	req, err := h.parseRequest(w, r)
+	if err == nil {
+		err = h.validate(&req)
+	}
+	if err != nil {
+		var reqErr *requestError
+		if errors.As(err, &reqErr) {
+			if reqErr.status == http.StatusMethodNotAllowed {
+				w.Header().Set("Allow", "GET, POST")
+			}
+			writeError(w, reqErr.status, reqErr.msg)
+			return
+		}
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	sw := &streamWriter{w: w, contentType: req.OutputFormat.MIMEType()}
+	sw.flusher, _ = w.(http.Flusher)
+	ttsReq := elevenlabs.TextToSpeechRequest{Text: req.Text, ModelID: req.ModelID, VoiceSettings: h.VoiceSettings}
+	queries := append([]elevenlabs.QueryFunc{elevenlabs.OutputFormat(req.OutputFormat)}, h.Queries...)
+	err = h.client.TextToSpeechStream(sw, req.VoiceID, ttsReq, queries...)
+	if err != nil && !sw.wroteHeader {
+		// Upstream errors (including API errors) are not forwarded as they may contain account details.
+		writeError(w, http.StatusBadGateway, "speech synthesis failed")
+		return
+	}
+	// If audio was already streamed, the status can no longer be changed and the response is left truncated.
+}
+
+// streamWriter writes the response headers on the first write and flushes the response after every write
+// so that audio reaches the client as it is being generated.
+type streamWriter struct {
+	w           http.ResponseWriter
+	flusher     http.Flusher
+	contentType string
+	wroteHeader bool
+}
+
+func (sw *streamWriter) Write(p []byte) (int, error) {
+	if !sw.wroteHeader {
+		sw.wroteHeader = true
+		sw.w.Header().Set("Content-Type", sw.contentType)
+		sw.w.Header().Set("Cache-Control", "no-store")
+		sw.w.WriteHeader(http.StatusOK)
+	}
+	n, err := sw.w.Write(p)
+	if err == nil && sw.flusher != nil {
+		sw.flusher.Flush()
+	}
+	return n, err
+}
@@ -0,0 +1,188 @@
+package proxy_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/haguro/elevenlabs-go"
+	"github.com/haguro/elevenlabs-go/proxy"
+)
+
+type fakeSynth struct {
+	chunks  []string
+	err     error
+	voiceID string
+	req     elevenlabs.TextToSpeechRequest
+	query   url.Values
+}
+
+func (s *fakeSynth) TextToSpeechStream(w io.Writer, voiceID string, req elevenlabs.TextToSpeechRequest, queries ...elevenlabs.QueryFunc) error {
+	s.voiceID, s.req, s.query = voiceID, req, url.Values{}
+	for _, qf := range queries {
+		qf(&s.query)
+	}
+	if s.err != nil {
+		return s.err
+	}
+	for _, c := range s.chunks {
+		if _, err := w.Write([]byte(c)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newTestHandler(synth *fakeSynth) *proxy.Handler {
+	h := proxy.NewHandler(synth, "defaultVoice")
+	h.AllowedVoices = []string{"otherVoice"}
+	h.AllowedModels = []string{"model1"}
+	h.AllowedFormats = []elevenlabs.AudioFormat{elevenlabs.FormatPCM_16000}
+	h.MaxTextLength = 20
+	h.Queries = []elevenlabs.QueryFunc{elevenlabs.LatencyOptimizations(2)}
+	return h
+}
+
+func TestHandlerStreamsAudio(t *testing.T) {
+	testCases := []struct {
+		name       string
+		req        *http.Request
+		expVoice   string
+		expModel   string
+		expFormat  string
+		expContent string
+	}{
+		{
+			name:       "GET with defaults",
+			req:        httptest.NewRequest(http.MethodGet, "/tts?text=Hello", nil),
+			expVoice:   "defaultVoice",
+			expFormat:  "mp3_44100_128",
+			expContent: "audio/mpeg",
+		},
+		{
+			name:       "POST JSON with allowed voice, model and format",
+			req:        httptest.NewRequest(http.MethodPost, "/tts", strings.NewReader(`{"text":"Hello","voice_id":"otherVoice","model_id":"model1","output_format":"pcm_16000"}`)),
+			expVoice:   "otherVoice",
+			expModel:   "model1",
+			expFormat:  "pcm_16000",
+			expContent: "audio/pcm",
+		},
+		{
+			name:       "POST form",
+			req:        httptest.NewRequest(http.MethodPost, "/tts", strings.NewReader("text=Hello&voice_id=otherVoice")),
+			expVoice:   "otherVoice",
+			expFormat:  "mp3_44100_128",
+			expContent: "audio/mpeg",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			switch {
+			case strings.HasPrefix(tc.name, "POST JSON"):
+				tc.req.Header.Set("Content-Type", "application/json")
+			case strings.HasPrefix(tc.name, "POST form"):
+				tc.req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			}
+			synth := &fakeSynth{chunks: []string{"audio", "bytes"}}
+			rec := httptest.NewRecorder()
+			newTestHandler(synth).ServeHTTP(rec, tc.req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body)
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != tc.expContent {
+				t.Errorf("Expected Content-Type %q, got %q", tc.expContent, ct)
+			}
+			if !rec.Flushed {
+				t.Error("Expected the response to be flushed while streaming")
+			}
+			if rec.Body.String() != "audiobytes" {
+				t.Errorf("Expected body %q, got %q", "audiobytes", rec.Body)
+			}
+			if synth.voiceID != tc.expVoice || synth.req.ModelID != tc.expModel || synth.req.Text != "Hello" {
+				t.Errorf("Unexpected request %+v for voice %q", synth.req, synth.voiceID)
+			}
+			if f := synth.query.Get("output_format"); f != tc.expFormat {
+				t.Errorf("Expected output format %q, got %q", tc.expFormat, f)
+			}
+			if l := synth.query.Get("optimize_streaming_latency"); l != "2" {
+				t.Errorf("Expected handler queries to be passed, got latency %q", l)
+			}
+		})
+	}
+}
+
+func TestHandlerZeroDefaultFormat(t *testing.T) {
+	synth := &fakeSynth{chunks: []string{"audio"}}
+	h := proxy.NewHandler(synth, "defaultVoice")
+	h.DefaultFormat = ""
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tts?text=Hello", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body)
+	}
+	if f := synth.query.Get("output_format"); f != string(elevenlabs.DefaultAudioFormat) {
+		t.Errorf("Expected output format %q, got %q", elevenlabs.DefaultAudioFormat, f)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tts?text=Hello&output_format=pcm_16000", nil))
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("Expected status %d, got %d", http.StatusForbidden, rec.Code)
+	}
+}
+
+func TestHandlerRejectsRequests(t *testing.T) {
+	testCases := []struct {
+		name      string
+		method    string
+		target    string
+		expStatus int
+		expError  string
+	}{
+		{"missing text", http.MethodGet, "/tts", http.StatusBadRequest, "text is required"},
+		{"text too long", http.MethodGet, "/tts?text=" + strings.Repeat("a", 21), http.StatusBadRequest, "text is too long"},
+		{"voice not allowed", http.MethodGet, "/tts?text=Hi&voice_id=secretVoice", http.StatusForbidden, "voice \"secretVoice\" is not allowed"},
+		{"model not allowed", http.MethodGet, "/tts?text=Hi&model_id=model2", http.StatusForbidden, "model \"model2\" is not allowed"},
+		{"format not allowed", http.MethodGet, "/tts?text=Hi&output_format=pcm_44100", http.StatusForbidden, "output format \"pcm_44100\" is not allowed"},
+		{"unknown format", http.MethodGet, "/tts?text=Hi&output_format=flac", http.StatusBadRequest, "unknown output format"},
+		{"wrong method", http.MethodDelete, "/tts?text=Hi", http.StatusMethodNotAllowed, "method not allowed"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			synth := &fakeSynth{}
+			rec := httptest.NewRecorder()
+			newTestHandler(synth).ServeHTTP(rec, httptest.NewRequest(tc.method, tc.target, nil))
+			if rec.Code != tc.expStatus {
+				t.Errorf("Expected status %d, got %d", tc.expStatus, rec.Code)
+			}
+			var body map[string]string
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("Expected a JSON error body, got %q", rec.Body)
+			}
+			if !strings.Contains(body["error"], tc.expError) {
+				t.Errorf("Expected error to contain %q, got %q", tc.expError, body["error"])
+			}
+			if synth.voiceID != "" {
+				t.Error("Expected no speech to be synthesized")
+			}
+		})
+	}
+}
+
+func TestHandlerUpstreamError(t *testing.T) {
+	synth := &fakeSynth{err: errors.New("api error - quota exceeded for account foo@example.com")}
+	rec := httptest.NewRecorder()
+	newTestHandler(synth).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tts?text=Hi", nil))
+	if rec.Code != http.StatusBadGateway {
+		t.Errorf("Expected status %d, got %d", http.StatusBadGateway, rec.Code)
+	}
+	if strings.Contains(rec.Body.String(), "example.com") {
+		t.Errorf("Expected upstream error details not to be exposed, got %q", rec.Body)
+	}
+}
@@ -0,0 +1,169 @@
+package elevenlabs
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultPruneConcurrency is the default number of history items deleted concurrently by PruneHistory.
+const DefaultPruneConcurrency = 4
+
+// RetentionPolicy represents the rules used by PruneHistory to decide which history items are deleted.
+//
+// A history item is deleted if it is older than MaxAge, unless it is among the KeepLatestPerVoice most recent
+// items of its voice or is protected by KeepWithFeedback. Without MaxAge, every history item that is not
+// protected is deleted if KeepLatestPerVoice is set. Zero valued limits are ignored, so the zero value of
+// RetentionPolicy deletes nothing.
+type RetentionPolicy struct {
+	// MaxAge is the age after which history items are deleted.
+	MaxAge time.Duration
+	// KeepLatestPerVoice is the number of most recent history items of each voice that are kept regardless of
+	// their age.
+	KeepLatestPerVoice int
+	// KeepWithFeedback protects the history items that received feedback from deletion.
+	KeepWithFeedback bool
+	// Filter restricts the history items the policy applies to. Other history items are left untouched and are
+	// not included in the report.
+	Filter HistoryFilter
+	// Now is the time the age of history items is measured against. Defaults to the current time.
+	Now time.Time
+	// DryRun reports which history items would be deleted without deleting them.
+	DryRun bool
+	// Concurrency is the number of history items deleted concurrently. Defaults to DefaultPruneConcurrency.
+	Concurrency int
+	// Interval is the minimum time between two delete requests, used to stay within the API rate limits.
+	// A value of 0 means no rate limiting.
+	Interval time.Duration
+}
+
+// PruneReport contains the outcome of PruneHistory.
+type PruneReport struct {
+	// Deleted contains the IDs of the deleted history items, or of the history items that would have been
+	// deleted in dry run mode.
+	Deleted []string
+	// Kept contains the IDs of the history items retained by the policy.
+	Kept []string
+	// Failed maps the IDs of the history items that could not be deleted to the cause.
+	Failed map[string]error
+}
+
+// PruneHistory deletes the history items that a given retention policy does not retain.
+//
+// It takes a RetentionPolicy argument and an optional list of QueryFunc 'queries' that are applied to every
+// history page request, such as PageSize or HistorySource.
+//
+// It returns a PruneReport and an error if the history could not be retrieved, in which case nothing is
+// deleted. Failures to delete individual items are recorded in the report instead.
+func (c *Client) PruneHistory(policy RetentionPolicy, queries ...QueryFunc) (PruneReport, error) {
+	report := PruneReport{Failed: map[string]error{}}
+	items, err := c.SearchHistory(policy.Filter, queries...)
+	if err != nil {
+		return report, err
+	}
+	toDelete := policy.apply(items)
+	for _, h := range items {
+		if !toDelete[h.HistoryItemId] {
+			report.Kept = append(report.Kept, h.HistoryItemId)
+		}
+	}
+
+	var ids []string
+	for _, h := range items {
+		if toDelete[h.HistoryItemId] {
+			ids = append(ids, h.HistoryItemId)
+		}
+	}
+	if policy.DryRun {
+		report.Deleted = ids
+		return report, nil
+	}
+
+	concurrency := policy.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultPruneConcurrency
+	}
+	var tick <-chan time.Time
+	if policy.Interval > 0 {
+		ticker := time.NewTicker(policy.Interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	queue := make(chan string)
+	var mu sync.Mutex
+	wg := sync.WaitGroup{}
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range queue {
+				err := c.ctx.Err()
+				if err == nil {
+					err = c.DeleteHistoryItem(id)
+				}
+				mu.Lock()
+				if err != nil {
+					report.Failed[id] = err
+				} else {
+					report.Deleted = append(report.Deleted, id)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for i, id := range ids {
+		if tick != nil && i > 0 {
+			select {
+			case <-tick:
+			case <-c.ctx.Done():
+			}
+		}
+		queue <- id
+	}
+	close(queue)
+	wg.Wait()
+	return report, nil
+}
+
+// apply returns the set of IDs of the given history items that are not retained by the policy.
+func (p RetentionPolicy) apply(items []HistoryItem) map[string]bool {
+	now := p.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	toDelete := map[string]bool{}
+	switch {
+	case p.MaxAge > 0:
+		cutoff := now.Add(-p.MaxAge)
+		for _, h := range items {
+			if h.Time().Before(cutoff) {
+				toDelete[h.HistoryItemId] = true
+			}
+		}
+	case p.KeepLatestPerVoice > 0:
+		for _, h := range items {
+			toDelete[h.HistoryItemId] = true
+		}
+	}
+	if p.KeepLatestPerVoice > 0 {
+		byVoice := map[string][]HistoryItem{}
+		for _, h := range items {
+			byVoice[h.VoiceId] = append(byVoice[h.VoiceId], h)
+		}
+		for _, voiceItems := range byVoice {
+			sort.SliceStable(voiceItems, func(i, j int) bool { return voiceItems[i].DateUnix > voiceItems[j].DateUnix })
+			for i := 0; i < p.KeepLatestPerVoice && i < len(voiceItems); i++ {
+				delete(toDelete, voiceItems[i].HistoryItemId)
+			}
+		}
+	}
+	if p.KeepWithFeedback {
+		for _, h := range items {
+			if h.HasFeedback() {
+				delete(toDelete, h.HistoryItemId)
+			}
+		}
+	}
+	return toDelete
+}
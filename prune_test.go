@@ -0,0 +1,131 @@
+package elevenlabs_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/haguro/elevenlabs-go"
+)
+
+func TestPruneHistory(t *testing.T) {
+	now := time.Unix(100*86400, 0)
+	day := func(d int) int { return int(now.Add(-time.Duration(d) * 24 * time.Hour).Unix()) }
+	items := []elevenlabs.HistoryItem{
+		{HistoryItemId: "a1", VoiceId: "a", DateUnix: day(1)},
+		{HistoryItemId: "b1", VoiceId: "b", DateUnix: day(2)},
+		{HistoryItemId: "a2", VoiceId: "a", DateUnix: day(3)},
+		{HistoryItemId: "a3", VoiceId: "a", DateUnix: day(4), Feedback: elevenlabs.Feedback{ThumbsUp: true}},
+		{HistoryItemId: "b2", VoiceId: "b", DateUnix: day(40)},
+		{HistoryItemId: "a4", VoiceId: "a", DateUnix: day(50)},
+		{HistoryItemId: "a5", VoiceId: "a", DateUnix: day(60), Feedback: elevenlabs.Feedback{Feedback: "great"}},
+	}
+
+	testCases := []struct {
+		name       string
+		policy     elevenlabs.RetentionPolicy
+		failID     string
+		expDeleted []string
+		expFailed  []string
+	}{
+		{
+			name:       "Max age",
+			policy:     elevenlabs.RetentionPolicy{MaxAge: 30 * 24 * time.Hour},
+			expDeleted: []string{"a4", "a5", "b2"},
+		},
+		{
+			name:       "Max age keeping items with feedback",
+			policy:     elevenlabs.RetentionPolicy{MaxAge: 30 * 24 * time.Hour, KeepWithFeedback: true},
+			expDeleted: []string{"a4", "b2"},
+		},
+		{
+			name:       "Latest per voice",
+			policy:     elevenlabs.RetentionPolicy{KeepLatestPerVoice: 2, Concurrency: 1, Interval: time.Millisecond},
+			expDeleted: []string{"a3", "a4", "a5"},
+		},
+		{
+			name:       "Max age keeping the latest per voice",
+			policy:     elevenlabs.RetentionPolicy{MaxAge: 30 * 24 * time.Hour, KeepLatestPerVoice: 2},
+			expDeleted: []string{"a4", "a5"},
+		},
+		{
+			name:       "Max age keeping the latest per voice and items with feedback",
+			policy:     elevenlabs.RetentionPolicy{MaxAge: 24 * time.Hour, KeepLatestPerVoice: 1, KeepWithFeedback: true},
+			expDeleted: []string{"a2", "a4", "b2"},
+		},
+		{
+			name:       "Restricted to a voice with a failure",
+			policy:     elevenlabs.RetentionPolicy{MaxAge: 24 * time.Hour, Filter: elevenlabs.HistoryFilter{VoiceID: "b"}},
+			failID:     "b2",
+			expDeleted: []string{"b1"},
+			expFailed:  []string{"b2"},
+		},
+		{
+			name:       "Dry run",
+			policy:     elevenlabs.RetentionPolicy{MaxAge: 45 * 24 * time.Hour, DryRun: true},
+			expDeleted: []string{"a4", "a5"},
+		},
+		{
+			name:   "Empty policy",
+			policy: elevenlabs.RetentionPolicy{},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var deleteCalls []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodGet && r.URL.Path == "/history" {
+					json.NewEncoder(w).Encode(elevenlabs.GetHistoryResponse{History: items})
+					return
+				}
+				id := strings.TrimPrefix(r.URL.Path, "/history/")
+				if r.Method != http.MethodDelete || id == r.URL.Path {
+					t.Errorf("Server: unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				mu.Lock()
+				deleteCalls = append(deleteCalls, id)
+				mu.Unlock()
+				if id == tc.failID {
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+			}))
+			defer server.Close()
+			client := elevenlabs.NewMockClient(context.Background(), server.URL, mockAPIKey, mockTimeout)
+
+			tc.policy.Now = now
+			report, err := client.PruneHistory(tc.policy)
+			if err != nil {
+				t.Fatalf("Expected no errors, got %q", err)
+			}
+			sort.Strings(report.Deleted)
+			if !reflect.DeepEqual(report.Deleted, tc.expDeleted) {
+				t.Errorf("Expected deleted items %v, got %v", tc.expDeleted, report.Deleted)
+			}
+			var failed []string
+			for id := range report.Failed {
+				failed = append(failed, id)
+			}
+			if !reflect.DeepEqual(failed, tc.expFailed) {
+				t.Errorf("Expected failed items %v, got %v", tc.expFailed, failed)
+			}
+			if len(report.Deleted)+len(report.Failed)+len(report.Kept) > len(items) {
+				t.Errorf("Expected each item to be reported once, got %+v", report)
+			}
+			expCalls := len(tc.expDeleted) + len(tc.expFailed)
+			if tc.policy.DryRun {
+				expCalls = 0
+			}
+			if len(deleteCalls) != expCalls {
+				t.Errorf("Expected %d delete requests, got %v", expCalls, deleteCalls)
+			}
+		})
+	}
+}
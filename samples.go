@@ -0,0 +1,124 @@
+package elevenlabs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// SampleReport contains the outcome of an operation on several samples of a voice.
+type SampleReport struct {
+	// Succeeded contains the IDs of the samples that were processed successfully.
+	Succeeded []string
+	// Failed maps the IDs of the samples that could not be processed to the cause.
+	Failed map[string]error
+	// Files maps the IDs of the samples downloaded by DownloadAllSamples to the path of their file.
+	Files map[string]string
+}
+
+func (r *SampleReport) record(sampleId string, err error) {
+	if err != nil {
+		if r.Failed == nil {
+			r.Failed = map[string]error{}
+		}
+		r.Failed[sampleId] = err
+		return
+	}
+	r.Succeeded = append(r.Succeeded, sampleId)
+}
+
+func (r SampleReport) err() error {
+	if len(r.Failed) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %d of %d samples", ErrPartialFailure, len(r.Failed), len(r.Failed)+len(r.Succeeded))
+}
+
+// editVoiceRequest returns an AddEditVoiceRequest that keeps the name, description and labels of a voice.
+func editVoiceRequest(v Voice, files []VoiceFile) AddEditVoiceRequest {
+	return AddEditVoiceRequest{Name: v.Name, Description: v.Description, Labels: v.Labels, Files: files}
+}
+
+// AddVoiceSamples uploads new samples to an existing voice, keeping its current samples, name, description
+// and labels.
+//
+// It takes a string argument that represents the ID of the voice and one or more VoiceFile arguments holding
+// the samples to upload.
+//
+// It returns nil if successful or an error otherwise. The samples are uploaded with a single EditVoice call,
+// so either all or none of them are added.
+func (c *Client) AddVoiceSamples(voiceId string, files ...VoiceFile) error {
+	v, err := c.GetVoice(voiceId)
+	if err != nil {
+		return err
+	}
+	return c.EditVoice(voiceId, editVoiceRequest(v, files))
+}
+
+// ReplaceVoiceSamples replaces all the samples of an existing voice. The new samples are uploaded before the
+// current ones are deleted so that the voice is never left without samples, which means the voice must have
+// room for the new samples in addition to the current ones.
+//
+// It takes a string argument that represents the ID of the voice and one or more VoiceFile arguments holding
+// the new samples.
+//
+// It returns a SampleReport of the deleted samples and an error if the new samples could not be uploaded, in
+// which case nothing is deleted, or an error wrapping ErrPartialFailure if some of the old samples could not
+// be deleted.
+func (c *Client) ReplaceVoiceSamples(voiceId string, files ...VoiceFile) (SampleReport, error) {
+	v, err := c.GetVoice(voiceId)
+	if err != nil {
+		return SampleReport{}, err
+	}
+	if err := c.EditVoice(voiceId, editVoiceRequest(v, files)); err != nil {
+		return SampleReport{}, err
+	}
+	var report SampleReport
+	for _, s := range v.Samples {
+		report.record(s.SampleId, c.DeleteSample(voiceId, s.SampleId))
+	}
+	return report, report.err()
+}
+
+// DownloadAllSamples downloads the audio of all the samples of a voice to a directory, which is created if it
+// does not exist. Each sample is written to a file named after the base of its original file name, prefixed
+// with its sample ID when the name is already used by another sample. Samples whose name is empty or does not
+// name a file in the directory, such as "..", are named after their sample ID instead.
+//
+// It takes a string argument that represents the ID of the voice and a string argument that represents the
+// path of the directory.
+//
+// It returns a SampleReport whose Files field maps the sample IDs to the paths of the written files, and an
+// error if the voice could not be retrieved or an error wrapping ErrPartialFailure if some of the samples
+// could not be downloaded.
+func (c *Client) DownloadAllSamples(voiceId, dir string) (SampleReport, error) {
+	v, err := c.GetVoice(voiceId)
+	if err != nil {
+		return SampleReport{}, err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return SampleReport{}, err
+	}
+	report := SampleReport{Files: map[string]string{}}
+	used := map[string]bool{}
+	for _, s := range v.Samples {
+		name := filepath.Base(s.FileName)
+		switch {
+		case name == "." || name == ".." || name == string(filepath.Separator):
+			name = s.SampleId
+		case used[name]:
+			name = s.SampleId + "_" + name
+		}
+		used[name] = true
+		path := filepath.Join(dir, name)
+		data, err := c.GetSampleAudio(voiceId, s.SampleId)
+		if err == nil {
+			err = os.WriteFile(path, data, 0o644)
+		}
+		if err == nil {
+			report.Files[s.SampleId] = path
+		}
+		report.record(s.SampleId, err)
+	}
+	return report, report.err()
+}
@@ -0,0 +1,167 @@
+package elevenlabs_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/haguro/elevenlabs-go"
+)
+
+// voiceEdit holds the name and files ("name:content") of an EditVoice request.
+type voiceEdit struct {
+	name  string
+	files []string
+}
+
+// voiceRoutes serves a single voice with three samples, failing the sample requests for the IDs in failIDs, and
+// records the EditVoice request in edit.
+func voiceRoutes(failIDs map[string]bool, edit *voiceEdit) map[string]http.HandlerFunc {
+	routes := map[string]http.HandlerFunc{
+		"GET /voices/v1": func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"voice_id":"v1","name":"Narrator","labels":{"accent":"british"},"samples":[
+			{"sample_id":"s1","file_name":"a.mp3"},{"sample_id":"s2","file_name":"a.mp3"},{"sample_id":"s3","file_name":""}]}`)
+		},
+		"POST /voices/v1/edit": func(w http.ResponseWriter, r *http.Request) {
+			_, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+			mr := multipart.NewReader(r.Body, params["boundary"])
+			for {
+				part, err := mr.NextPart()
+				if err != nil {
+					break
+				}
+				b, _ := io.ReadAll(part)
+				switch part.FormName() {
+				case "files":
+					edit.files = append(edit.files, part.FileName()+":"+string(b))
+				case "name":
+					edit.name = string(b)
+				}
+			}
+		},
+	}
+	for _, id := range []string{"s1", "s2", "s3"} {
+		id := id
+		sample := func(w http.ResponseWriter, r *http.Request) {
+			if failIDs[id] {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			fmt.Fprintf(w, "audio-%s", id)
+		}
+		routes["GET /voices/v1/samples/"+id+"/audio"] = sample
+		routes["DELETE /voices/v1/samples/"+id] = sample
+	}
+	return routes
+}
+
+func TestAddVoiceSamples(t *testing.T) {
+	edit := &voiceEdit{}
+	log := &requestLog{}
+	server := testServer(t, testServerConfig{routes: voiceRoutes(nil, edit), log: log})
+	defer server.Close()
+	client := elevenlabs.NewMockClient(context.Background(), server.URL, mockAPIKey, mockTimeout)
+
+	err := client.AddVoiceSamples("v1", elevenlabs.VoiceFile{Name: "new.mp3", Reader: strings.NewReader("new")})
+	if err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+	if edit.name != "Narrator" || !reflect.DeepEqual(edit.files, []string{"new.mp3:new"}) || log.count("POST /voices/v1/edit") != 1 {
+		t.Errorf("Unexpected EditVoice request: name %q, files %v, requests %v", edit.name, edit.files, log.list())
+	}
+}
+
+func TestReplaceVoiceSamples(t *testing.T) {
+	log := &requestLog{}
+	server := testServer(t, testServerConfig{routes: voiceRoutes(map[string]bool{"s2": true}, &voiceEdit{}), log: log})
+	defer server.Close()
+	client := elevenlabs.NewMockClient(context.Background(), server.URL, mockAPIKey, mockTimeout)
+
+	report, err := client.ReplaceVoiceSamples("v1", elevenlabs.VoiceFile{Name: "new.mp3", Reader: strings.NewReader("new")})
+	if !errors.Is(err, elevenlabs.ErrPartialFailure) {
+		t.Errorf("Expected error %q, got %v", elevenlabs.ErrPartialFailure, err)
+	}
+	exp := []string{"GET /voices/v1", "POST /voices/v1/edit", "DELETE /voices/v1/samples/s1", "DELETE /voices/v1/samples/s2", "DELETE /voices/v1/samples/s3"}
+	if got := log.list(); !reflect.DeepEqual(got, exp) {
+		t.Errorf("Expected requests %v, got %v", exp, got)
+	}
+	if !reflect.DeepEqual(report.Succeeded, []string{"s1", "s3"}) || len(report.Failed) != 1 || report.Failed["s2"] == nil {
+		t.Errorf("Unexpected report %+v", report)
+	}
+}
+
+func TestDownloadAllSamples(t *testing.T) {
+	server := testServer(t, testServerConfig{routes: voiceRoutes(map[string]bool{"s3": true}, &voiceEdit{})})
+	defer server.Close()
+	client := elevenlabs.NewMockClient(context.Background(), server.URL, mockAPIKey, mockTimeout)
+
+	dir := filepath.Join(t.TempDir(), "samples")
+	report, err := client.DownloadAllSamples("v1", dir)
+	if !errors.Is(err, elevenlabs.ErrPartialFailure) {
+		t.Errorf("Expected error %q, got %v", elevenlabs.ErrPartialFailure, err)
+	}
+	expFiles := map[string]string{"s1": filepath.Join(dir, "a.mp3"), "s2": filepath.Join(dir, "s2_a.mp3")}
+	if !reflect.DeepEqual(report.Files, expFiles) || report.Failed["s3"] == nil {
+		t.Errorf("Unexpected report %+v", report)
+	}
+	for id, path := range expFiles {
+		if b, err := os.ReadFile(path); err != nil || string(b) != "audio-"+id {
+			t.Errorf("Expected file %s to contain %q, got %q (error: %v)", path, "audio-"+id, b, err)
+		}
+	}
+	entries, _ := os.ReadDir(dir)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+	if exp := []string{"a.mp3", "s2_a.mp3"}; !reflect.DeepEqual(names, exp) {
+		t.Errorf("Expected files %v, got %v", exp, names)
+	}
+}
+
+func TestDownloadAllSamplesUnsafeNames(t *testing.T) {
+	routes := map[string]http.HandlerFunc{
+		"GET /voices/v1": func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"voice_id":"v1","samples":[{"sample_id":"s1","file_name":".."},{"sample_id":"s2","file_name":"/"},
+			{"sample_id":"s3","file_name":"a/.."},{"sample_id":"s4","file_name":"../b.mp3"}]}`)
+		},
+	}
+	for _, id := range []string{"s1", "s2", "s3", "s4"} {
+		id := id
+		routes["GET /voices/v1/samples/"+id+"/audio"] = func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, "audio-%s", id)
+		}
+	}
+	server := testServer(t, testServerConfig{routes: routes})
+	defer server.Close()
+	client := elevenlabs.NewMockClient(context.Background(), server.URL, mockAPIKey, mockTimeout)
+
+	dir := filepath.Join(t.TempDir(), "samples")
+	report, err := client.DownloadAllSamples("v1", dir)
+	if err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+	expFiles := map[string]string{"s1": filepath.Join(dir, "s1"), "s2": filepath.Join(dir, "s2"), "s3": filepath.Join(dir, "s3"), "s4": filepath.Join(dir, "b.mp3")}
+	if !reflect.DeepEqual(report.Files, expFiles) {
+		t.Errorf("Expected files %v, got %v", expFiles, report.Files)
+	}
+	for id, path := range expFiles {
+		if b, err := os.ReadFile(path); err != nil || string(b) != "audio-"+id {
+			t.Errorf("Expected file %s to contain %q, got %q (error: %v)", path, "audio-"+id, b, err)
+		}
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(dir)); len(entries) != 1 {
+		t.Errorf("Expected no files to be written outside of %s, got %d entries", dir, len(entries))
+	}
+}
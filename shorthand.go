@@ -3,7 +3,10 @@
 
 package elevenlabs
 
-import "io"
+import (
+	"io"
+	"time"
+)
 
 // TextToSpeech calls the TextToSpeech method on the default client.
 func TextToSpeech(voiceID string, ttsReq TextToSpeechRequest, queries ...QueryFunc) ([]byte, error) {
@@ -75,6 +78,11 @@ func GetHistory(queries ...QueryFunc) (GetHistoryResponse, NextHistoryPageFunc,
 	return getDefaultClient().GetHistory(queries...)
 }
 
+// HistoryIterator calls the HistoryIterator method on the default client.
+func HistoryIterator(queries ...QueryFunc) *Iterator[HistoryItem] {
+	return getDefaultClient().HistoryIterator(queries...)
+}
+
 // GetHistoryItem calls the GetHistoryItem method on the default client.
 func GetHistoryItem(itemId string) (HistoryItem, error) {
 	return getDefaultClient().GetHistoryItem(itemId)
@@ -95,6 +103,21 @@ func DownloadHistoryAudio(dlReq DownloadHistoryRequest) ([]byte, error) {
 	return getDefaultClient().DownloadHistoryAudio(dlReq)
 }
 
+// SubmitHistoryFeedback calls the SubmitHistoryFeedback method on the default client.
+func SubmitHistoryFeedback(itemId string, feedback HistoryFeedbackRequest) error {
+	return getDefaultClient().SubmitHistoryFeedback(itemId, feedback)
+}
+
+// CreateHistoryShareLink calls the CreateHistoryShareLink method on the default client.
+func CreateHistoryShareLink(itemId string) (string, error) {
+	return getDefaultClient().CreateHistoryShareLink(itemId)
+}
+
+// DeleteHistoryShareLink calls the DeleteHistoryShareLink method on the default client.
+func DeleteHistoryShareLink(itemId string) error {
+	return getDefaultClient().DeleteHistoryShareLink(itemId)
+}
+
 // GetSubscription calls the GetSubscription method on the default client.
 func GetSubscription() (Subscription, error) {
 	return getDefaultClient().GetSubscription()
@@ -104,3 +127,133 @@ func GetSubscription() (Subscription, error) {
 func GetUser() (User, error) {
 	return getDefaultClient().GetUser()
 }
+
+// ExportHistory calls the ExportHistory method on the default client.
+func ExportHistory(opts ExportOptions) (ExportReport, error) {
+	return getDefaultClient().ExportHistory(opts)
+}
+
+// ValidateOutputFormat calls the ValidateOutputFormat method on the default client.
+func ValidateOutputFormat(format AudioFormat) error {
+	return getDefaultClient().ValidateOutputFormat(format)
+}
+
+// SetSubscriptionCacheTTL calls the SetSubscriptionCacheTTL method on the default client.
+func SetSubscriptionCacheTTL(ttl time.Duration) {
+	getDefaultClient().SetSubscriptionCacheTTL(ttl)
+}
+
+// SearchHistory calls the SearchHistory method on the default client.
+func SearchHistory(filter HistoryFilter, queries ...QueryFunc) ([]HistoryItem, error) {
+	return getDefaultClient().SearchHistory(filter, queries...)
+}
+
+// DownloadHistoryAudioFiles calls the DownloadHistoryAudioFiles method on the default client.
+func DownloadHistoryAudioFiles(dlReq DownloadHistoryRequest) (HistoryAudio, error) {
+	return getDefaultClient().DownloadHistoryAudioFiles(dlReq)
+}
+
+// DownloadHistoryAudioStream calls the DownloadHistoryAudioStream method on the default client.
+func DownloadHistoryAudioStream(w io.Writer, dlReq DownloadHistoryRequest) error {
+	return getDefaultClient().DownloadHistoryAudioStream(w, dlReq)
+}
+
+// SetModelCacheTTL calls the SetModelCacheTTL method on the default client.
+func SetModelCacheTTL(ttl time.Duration) {
+	getDefaultClient().SetModelCacheTTL(ttl)
+}
+
+// CachedModels calls the CachedModels method on the default client.
+func CachedModels() ([]Model, error) {
+	return getDefaultClient().CachedModels()
+}
+
+// FindModel calls the FindModel method on the default client.
+func FindModel(modelId string) (Model, error) {
+	return getDefaultClient().FindModel(modelId)
+}
+
+// ValidateTextToSpeech calls the ValidateTextToSpeech method on the default client.
+func ValidateTextToSpeech(voiceId string, ttsReq TextToSpeechRequest, languageId string) error {
+	return getDefaultClient().ValidateTextToSpeech(voiceId, ttsReq, languageId)
+}
+
+// PruneHistory calls the PruneHistory method on the default client.
+func PruneHistory(policy RetentionPolicy, queries ...QueryFunc) (PruneReport, error) {
+	return getDefaultClient().PruneHistory(policy, queries...)
+}
+
+// AddVoiceSamples calls the AddVoiceSamples method on the default client.
+func AddVoiceSamples(voiceId string, files ...VoiceFile) error {
+	return getDefaultClient().AddVoiceSamples(voiceId, files...)
+}
+
+// ReplaceVoiceSamples calls the ReplaceVoiceSamples method on the default client.
+func ReplaceVoiceSamples(voiceId string, files ...VoiceFile) (SampleReport, error) {
+	return getDefaultClient().ReplaceVoiceSamples(voiceId, files...)
+}
+
+// DownloadAllSamples calls the DownloadAllSamples method on the default client.
+func DownloadAllSamples(voiceId, dir string) (SampleReport, error) {
+	return getDefaultClient().DownloadAllSamples(voiceId, dir)
+}
+
+// RecordedTextToSpeechStream calls the RecordedTextToSpeechStream method on the default client.
+func RecordedTextToSpeechStream(streamWriter io.Writer, voiceID string, ttsReq TextToSpeechRequest, queries ...QueryFunc) (TextToSpeechRecord, error) {
+	return getDefaultClient().RecordedTextToSpeechStream(streamWriter, voiceID, ttsReq, queries...)
+}
+
+// RecordedTextToSpeech calls the RecordedTextToSpeech method on the default client.
+func RecordedTextToSpeech(voiceID string, ttsReq TextToSpeechRequest, queries ...QueryFunc) ([]byte, TextToSpeechRecord, error) {
+	return getDefaultClient().RecordedTextToSpeech(voiceID, ttsReq, queries...)
+}
+
+// ReplayStream calls the ReplayStream method on the default client.
+func ReplayStream(streamWriter io.Writer, record TextToSpeechRecord, text string) error {
+	return getDefaultClient().ReplayStream(streamWriter, record, text)
+}
+
+// Replay calls the Replay method on the default client.
+func Replay(record TextToSpeechRecord, text string) ([]byte, error) {
+	return getDefaultClient().Replay(record, text)
+}
+
+// SetVoiceCacheTTL calls the SetVoiceCacheTTL method on the default client.
+func SetVoiceCacheTTL(ttl time.Duration) {
+	getDefaultClient().SetVoiceCacheTTL(ttl)
+}
+
+// InvalidateVoiceCache calls the InvalidateVoiceCache method on the default client.
+func InvalidateVoiceCache() {
+	getDefaultClient().InvalidateVoiceCache()
+}
+
+// CachedVoices calls the CachedVoices method on the default client.
+func CachedVoices() ([]Voice, error) {
+	return getDefaultClient().CachedVoices()
+}
+
+// FindVoices calls the FindVoices method on the default client.
+func FindVoices(query VoiceQuery) ([]Voice, error) {
+	return getDefaultClient().FindVoices(query)
+}
+
+// FindVoice calls the FindVoice method on the default client.
+func FindVoice(name string) (Voice, error) {
+	return getDefaultClient().FindVoice(name)
+}
+
+// ResolveVoiceID calls the ResolveVoiceID method on the default client.
+func ResolveVoiceID(nameOrId string) (string, error) {
+	return getDefaultClient().ResolveVoiceID(nameOrId)
+}
+
+// MergeVoiceSettings calls the MergeVoiceSettings method on the default client.
+func MergeVoiceSettings(voiceId string, o VoiceSettingsOverride) (VoiceSettings, error) {
+	return getDefaultClient().MergeVoiceSettings(voiceId, o)
+}
+
+// ValidateVoiceRequest calls the ValidateVoiceRequest method on the default client.
+func ValidateVoiceRequest(voiceReq *AddEditVoiceRequest, limits VoiceSampleLimits, newVoice bool) error {
+	return getDefaultClient().ValidateVoiceRequest(voiceReq, limits, newVoice)
+}
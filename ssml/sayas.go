@@ -0,0 +1,120 @@
+package ssml
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/haguro/elevenlabs-go/textnorm"
+)
+
+// english reads the content of say-as tags.
+var english = textnorm.English{}
+
+// sayAs returns the words for the content of a say-as tag.
+func sayAs(content, interpretAs, format string) (string, error) {
+	switch interpretAs {
+	case "cardinal", "number":
+		return cardinal(content)
+	case "ordinal":
+		n, err := strconv.ParseInt(strings.TrimRight(strings.ReplaceAll(content, ",", ""), "stndrh"), 10, 64)
+		if err != nil || n < 0 {
+			return "", fmt.Errorf("invalid ordinal %q", content)
+		}
+		return english.Ordinal(n), nil
+	case "digits":
+		return spell(content, true)
+	case "characters", "spell-out":
+		return spell(content, false)
+	case "telephone":
+		return telephone(content)
+	case "date":
+		return date(content, format)
+	case "":
+		return "", fmt.Errorf("<say-as> requires an interpret-as attribute")
+	}
+	return "", fmt.Errorf("unsupported interpret-as %q", interpretAs)
+}
+
+// cardinal returns the words for a number such as "-1,234.5".
+func cardinal(s string) (string, error) {
+	words, ok := english.Number(s)
+	if !ok {
+		return "", fmt.Errorf("invalid number %q", s)
+	}
+	return words, nil
+}
+
+// spell returns the characters of s separated by spaces, with digits as words. If digitsOnly is true, s may
+// only contain digits and spaces.
+func spell(s string, digitsOnly bool) (string, error) {
+	var words []string
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			words = append(words, english.Cardinal(int64(r-'0')))
+		case r == ' ':
+		case digitsOnly:
+			return "", fmt.Errorf("invalid digits %q", s)
+		default:
+			words = append(words, string(r))
+		}
+	}
+	if len(words) == 0 {
+		return "", fmt.Errorf("nothing to spell")
+	}
+	return strings.Join(words, " "), nil
+}
+
+// telephone returns the words for a telephone number, reading the digits of each group separately.
+func telephone(s string) (string, error) {
+	var groups []string
+	plus := strings.HasPrefix(strings.TrimSpace(s), "+")
+	for _, g := range strings.FieldsFunc(s, func(r rune) bool { return r < '0' || r > '9' }) {
+		words, _ := spell(g, true)
+		groups = append(groups, words)
+	}
+	if len(groups) == 0 || strings.Trim(s, "0123456789+-.() ") != "" {
+		return "", fmt.Errorf("invalid telephone number %q", s)
+	}
+	words := strings.Join(groups, ", ")
+	if plus {
+		words = "plus " + words
+	}
+	return words, nil
+}
+
+// date returns the words for a date such as "12/25/2024". The format is a combination of the letters d, m and
+// y giving the order of the fields, and defaults to "mdy".
+func date(s, format string) (string, error) {
+	if format == "" {
+		format = "mdy"
+	}
+	fields := strings.FieldsFunc(s, func(r rune) bool { return r == '/' || r == '-' || r == '.' || r == ' ' })
+	if len(fields) != len(format) || strings.Trim(format, "dmy") != "" {
+		return "", fmt.Errorf("date %q does not match format %q", s, format)
+	}
+	// Fields that are not in the format are left at 0, which textnorm.English.Date leaves out.
+	var day, month, year int
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 {
+			return "", fmt.Errorf("invalid date %q", s)
+		}
+		switch format[i] {
+		case 'd':
+			day = n
+			if n < 1 || n > 31 {
+				return "", fmt.Errorf("invalid date %q", s)
+			}
+		case 'm':
+			month = n
+			if n < 1 || n > 12 {
+				return "", fmt.Errorf("invalid date %q", s)
+			}
+		case 'y':
+			year = n
+		}
+	}
+	return english.Date(year, month, day), nil
+}
@@ -0,0 +1,88 @@
+package ssml
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/haguro/elevenlabs-go"
+)
+
+// ErrSilenceFormat is returned when silence cannot be generated for an audio format. Silence can only be
+// inserted between segments of PCM, μ-law and A-law audio, as other formats cannot simply be concatenated.
+var ErrSilenceFormat = errors.New("ssml: silence not supported for audio format")
+
+// API is the part of elevenlabs.Client used by Speak.
+type API interface {
+	TextToSpeechStream(streamWriter io.Writer, voiceID string, ttsReq elevenlabs.TextToSpeechRequest, queries ...elevenlabs.QueryFunc) error
+}
+
+// Silence returns a given duration of silence in an audio format.
+//
+// It returns the raw audio, or an error wrapping ErrSilenceFormat if the format is not a PCM, μ-law or
+// A-law format.
+func Silence(format elevenlabs.AudioFormat, d time.Duration) ([]byte, error) {
+	var sample byte
+	switch format.Codec() {
+	case elevenlabs.CodecPCM:
+		sample = 0
+	case elevenlabs.CodecULaw:
+		sample = 0xFF
+	case elevenlabs.CodecALaw:
+		sample = 0xD5
+	default:
+		return nil, fmt.Errorf("%w %q", ErrSilenceFormat, format)
+	}
+	samples := int(d.Seconds() * float64(format.SampleRate()))
+	return bytes.Repeat([]byte{sample}, samples*format.BytesPerSample()), nil
+}
+
+// Speak synthesizes segments returned by Convert one request at a time and writes the audio to a writer, with
+// the silence of each segment in between. The text of the neighbouring segments is sent as PreviousText and
+// NextText, unless the request sets them, so that the speech sounds continuous across segments.
+//
+// It takes an io.Writer argument to which the audio is written, an API argument (typically an
+// *elevenlabs.Client), a string argument that represents the ID of the voice, a TextToSpeechRequest argument
+// that is used for every segment with the text replaced, the segments, the output format, which must support
+// silence if any segment has some, and an optional list of QueryFunc 'queries'.
+//
+// It returns nil if successful, an error wrapping ErrSilenceFormat if the format does not support silence, in
+// which case nothing is written, or an error.
+func Speak(w io.Writer, client API, voiceID string, ttsReq elevenlabs.TextToSpeechRequest, segments []Segment, format elevenlabs.AudioFormat, queries ...elevenlabs.QueryFunc) error {
+	for _, s := range segments {
+		if s.Silence > 0 {
+			if _, err := Silence(format, 0); err != nil {
+				return err
+			}
+			break
+		}
+	}
+	queries = append([]elevenlabs.QueryFunc{elevenlabs.OutputFormat(format)}, queries...)
+	for i, s := range segments {
+		if s.Text != "" {
+			req := ttsReq
+			req.Text = s.Text
+			if req.PreviousText == "" && i > 0 {
+				req.PreviousText = segments[i-1].Text
+			}
+			if req.NextText == "" && i < len(segments)-1 {
+				req.NextText = segments[i+1].Text
+			}
+			if err := client.TextToSpeechStream(w, voiceID, req, queries...); err != nil {
+				return err
+			}
+		}
+		if s.Silence > 0 {
+			silence, err := Silence(format, s.Silence)
+			if err != nil {
+				return err
+			}
+			if _, err := w.Write(silence); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package ssml_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/haguro/elevenlabs-go"
+	"github.com/haguro/elevenlabs-go/ssml"
+)
+
+// fakeAPI writes the text of each request as audio and records the requests.
+type fakeAPI struct {
+	reqs    []elevenlabs.TextToSpeechRequest
+	formats []string
+}
+
+func (f *fakeAPI) TextToSpeechStream(w io.Writer, voiceID string, ttsReq elevenlabs.TextToSpeechRequest, queries ...elevenlabs.QueryFunc) error {
+	q := url.Values{}
+	for _, qf := range queries {
+		qf(&q)
+	}
+	f.reqs = append(f.reqs, ttsReq)
+	f.formats = append(f.formats, q.Get("output_format"))
+	_, err := fmt.Fprintf(w, "[%s]", ttsReq.Text)
+	return err
+}
+
+func TestSilence(t *testing.T) {
+	tests := []struct {
+		format   elevenlabs.AudioFormat
+		expected []byte
+	}{
+		{elevenlabs.FormatPCM_16000, make([]byte, 3200)},
+		{elevenlabs.FormatULaw_8000, bytes.Repeat([]byte{0xFF}, 800)},
+		{elevenlabs.FormatALaw_8000, bytes.Repeat([]byte{0xD5}, 800)},
+	}
+	for _, tc := range tests {
+		t.Run(string(tc.format), func(t *testing.T) {
+			silence, err := ssml.Silence(tc.format, 100*time.Millisecond)
+			if err != nil {
+				t.Fatalf("Expected no errors, got %q", err)
+			}
+			if !bytes.Equal(silence, tc.expected) {
+				t.Errorf("Expected %d bytes of silence, got %d", len(tc.expected), len(silence))
+			}
+		})
+	}
+	if _, err := ssml.Silence(elevenlabs.FormatMP3_44100_128, time.Second); !errors.Is(err, ssml.ErrSilenceFormat) {
+		t.Errorf("Expected error %v, got %v", ssml.ErrSilenceFormat, err)
+	}
+}
+
+func TestSpeak(t *testing.T) {
+	segments, err := ssml.Convert(`One. <break time="5s"/> Two. <break time="1s"/> Three.`, ssml.Options{})
+	if err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+	api := &fakeAPI{}
+	b := bytes.Buffer{}
+	err = ssml.Speak(&b, api, "v1", elevenlabs.TextToSpeechRequest{ModelID: "model1"}, segments, elevenlabs.FormatULaw_8000)
+	if err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+
+	expected := "[One.]" + string(bytes.Repeat([]byte{0xFF}, 40000)) + "[Two.]" + string(bytes.Repeat([]byte{0xFF}, 8000)) + "[Three.]"
+	if b.String() != expected {
+		t.Errorf("Unexpected audio of %d bytes, expected %d", b.Len(), len(expected))
+	}
+	if len(api.reqs) != 3 {
+		t.Fatalf("Expected 3 requests, got %d", len(api.reqs))
+	}
+	second := api.reqs[1]
+	if second.ModelID != "model1" || second.PreviousText != "One." || second.NextText != "Three." || api.formats[1] != string(elevenlabs.FormatULaw_8000) {
+		t.Errorf("Unexpected request %+v with output format %q", second, api.formats[1])
+	}
+
+	api = &fakeAPI{}
+	b.Reset()
+	err = ssml.Speak(&b, api, "v1", elevenlabs.TextToSpeechRequest{}, segments, elevenlabs.FormatMP3_44100_128)
+	if !errors.Is(err, ssml.ErrSilenceFormat) {
+		t.Errorf("Expected error %v, got %v", ssml.ErrSilenceFormat, err)
+	}
+	if len(api.reqs) != 0 || b.Len() != 0 {
+		t.Errorf("Expected nothing to be synthesized for a format without silence, got %d requests", len(api.reqs))
+	}
+}
@@ -0,0 +1,430 @@
+// Package ssml converts text marked up with a subset of SSML into text that Elevenlabs models understand.
+//
+// The supported tags are:
+//
+//	<speak>...</speak>                            optional root element
+//	<break time="1.5s"/> or <break strength="weak"/> a pause
+//	<phoneme alphabet="ipa" ph="...">word</phoneme>  a pronunciation hint ("ipa" or "cmu-arpabet")
+//	<sub alias="World Wide Web">WWW</sub>            a substitution
+//	<say-as interpret-as="...">...</say-as>          numbers, dates and the like read out as words
+//
+// Breaks and phonemes are kept as tags when the model supports them. Other breaks are turned into separate
+// segments with silence in between, which Speak synthesizes with one request per segment. Text in the output is
+// escaped, so that entities such as &lt; in the input are never turned into tags.
+package ssml
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultMaxBreak is the longest pause that Elevenlabs models support with a break tag.
+const DefaultMaxBreak = 3 * time.Second
+
+// Options controls how marked up text is converted.
+type Options struct {
+	// Breaks keeps pauses of up to MaxBreak as break tags. Longer pauses, or all pauses if Breaks is false,
+	// split the text into segments separated by silence.
+	Breaks   bool
+	MaxBreak time.Duration
+	// Phonemes keeps phoneme tags. If false, the text of phoneme tags is used without a pronunciation hint.
+	Phonemes bool
+}
+
+// DefaultOptions are the options suited to most models, which support break tags but not phoneme tags.
+var DefaultOptions = Options{Breaks: true, MaxBreak: DefaultMaxBreak}
+
+// phonemeModels are the models that support phoneme tags.
+var phonemeModels = map[string]bool{
+	"eleven_flash_v2":       true,
+	"eleven_turbo_v2":       true,
+	"eleven_monolingual_v1": true,
+}
+
+// OptionsForModel returns the options suited to a model, given its ID.
+func OptionsForModel(modelId string) Options {
+	opts := DefaultOptions
+	opts.Phonemes = phonemeModels[modelId]
+	return opts
+}
+
+// Segment is a part of the converted text. Its Text, if any, is synthesized and followed by Silence.
+type Segment struct {
+	Text    string
+	Silence time.Duration
+}
+
+// SyntaxError describes invalid markup and where it was found.
+type SyntaxError struct {
+	// Offset is the byte offset of the error in the input.
+	Offset int
+	// Line and Column are the 1-based line and column (in characters) of the error.
+	Line, Column int
+	Msg          string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("ssml: line %d, column %d: %s", e.Line, e.Column, e.Msg)
+}
+
+// breakStrengths maps the values of the strength attribute of break tags to a pause.
+var breakStrengths = map[string]time.Duration{
+	"none":     0,
+	"x-weak":   250 * time.Millisecond,
+	"weak":     500 * time.Millisecond,
+	"medium":   750 * time.Millisecond,
+	"strong":   time.Second,
+	"x-strong": 1250 * time.Millisecond,
+}
+
+// Convert converts marked up text into segments, using a set of options (typically DefaultOptions or the
+// result of OptionsForModel).
+//
+// It returns the segments, or a *SyntaxError if the markup is invalid or uses unsupported tags or attributes.
+func Convert(input string, opts Options) ([]Segment, error) {
+	c := converter{parser: parser{src: input}, opts: opts}
+	if err := c.run(); err != nil {
+		return nil, err
+	}
+	c.flush(0)
+	return c.segments, nil
+}
+
+// Text converts marked up text that contains no pauses requiring silence, such as a single sentence, into a
+// single string.
+//
+// It returns the text, or an error if the markup is invalid or would need to be split into segments.
+func Text(input string, opts Options) (string, error) {
+	segments, err := Convert(input, opts)
+	if err != nil {
+		return "", err
+	}
+	switch len(segments) {
+	case 0:
+		return "", nil
+	case 1:
+		if segments[0].Silence == 0 {
+			return segments[0].Text, nil
+		}
+	}
+	return "", fmt.Errorf("ssml: text requires %d segments", len(segments))
+}
+
+// textEscaper escapes the characters of output text that could otherwise be read as markup. Unlike
+// html.EscapeString, it leaves quotes unchanged.
+var textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+type converter struct {
+	parser
+	opts     Options
+	text     strings.Builder
+	segments []Segment
+}
+
+// flush ends the current segment with a silence.
+func (c *converter) flush(silence time.Duration) {
+	text := strings.TrimSpace(c.text.String())
+	c.text.Reset()
+	if text == "" && len(c.segments) > 0 && c.segments[len(c.segments)-1].Silence > 0 {
+		c.segments[len(c.segments)-1].Silence += silence
+		return
+	}
+	if text == "" && silence == 0 {
+		return
+	}
+	c.segments = append(c.segments, Segment{Text: text, Silence: silence})
+}
+
+func (c *converter) run() error {
+	inSpeak := false
+	speakAt := 0
+	for c.pos < len(c.src) {
+		if c.src[c.pos] != '<' {
+			end := strings.IndexByte(c.src[c.pos:], '<')
+			if end < 0 {
+				end = len(c.src) - c.pos
+			}
+			c.text.WriteString(textEscaper.Replace(html.UnescapeString(c.src[c.pos : c.pos+end])))
+			c.pos += end
+			continue
+		}
+		if strings.HasPrefix(c.src[c.pos:], "<!--") {
+			end := strings.Index(c.src[c.pos+4:], "-->")
+			if end < 0 {
+				return c.errorAt(c.pos, "unterminated comment")
+			}
+			c.pos += 4 + end + 3
+			continue
+		}
+
+		t, err := c.tag()
+		if err != nil {
+			return err
+		}
+		switch {
+		case t.name == "speak" && t.closing:
+			if !inSpeak {
+				return c.errorAt(t.offset, "unexpected </speak>")
+			}
+			inSpeak = false
+		case t.closing:
+			return c.errorAt(t.offset, "unexpected </%s>", t.name)
+		case t.name == "speak":
+			if inSpeak {
+				return c.errorAt(t.offset, "<speak> cannot be nested")
+			}
+			if err := c.checkAttrs(t); err != nil {
+				return err
+			}
+			inSpeak, speakAt = !t.selfClosing, t.offset
+		case t.name == "break":
+			if err := c.breakTag(t); err != nil {
+				return err
+			}
+		case t.name == "phoneme" || t.name == "sub" || t.name == "say-as":
+			if err := c.contentTag(t); err != nil {
+				return err
+			}
+		default:
+			return c.errorAt(t.offset, "unsupported tag <%s>", t.name)
+		}
+	}
+	if inSpeak {
+		return c.errorAt(speakAt, "unclosed <speak>")
+	}
+	return nil
+}
+
+func (c *converter) breakTag(t tag) error {
+	if err := c.checkAttrs(t, "time", "strength"); err != nil {
+		return err
+	}
+	if !t.selfClosing {
+		content, err := c.content(t)
+		if err != nil {
+			return err
+		}
+		if strings.TrimSpace(content) != "" {
+			return c.errorAt(t.offset, "<break> cannot contain text")
+		}
+	}
+	var d time.Duration
+	switch {
+	case t.attrs["time"] != "":
+		var err error
+		d, err = time.ParseDuration(t.attrs["time"])
+		if err != nil || d < 0 {
+			return c.errorAt(t.offset, "invalid break time %q", t.attrs["time"])
+		}
+	case t.attrs["strength"] != "":
+		var ok bool
+		if d, ok = breakStrengths[t.attrs["strength"]]; !ok {
+			return c.errorAt(t.offset, "invalid break strength %q", t.attrs["strength"])
+		}
+	default:
+		d = breakStrengths["medium"]
+	}
+	switch {
+	case d == 0:
+	case c.opts.Breaks && d <= c.opts.MaxBreak:
+		fmt.Fprintf(&c.text, `<break time="%ss" />`, strconv.FormatFloat(d.Seconds(), 'f', -1, 64))
+	default:
+		c.flush(d)
+	}
+	return nil
+}
+
+func (c *converter) contentTag(t tag) error {
+	var err error
+	switch t.name {
+	case "phoneme":
+		err = c.checkAttrs(t, "alphabet", "ph")
+		if err == nil && t.attrs["alphabet"] != "ipa" && t.attrs["alphabet"] != "cmu-arpabet" {
+			err = c.errorAt(t.offset, "unsupported phoneme alphabet %q", t.attrs["alphabet"])
+		}
+		if err == nil && t.attrs["ph"] == "" {
+			err = c.errorAt(t.offset, "<phoneme> requires a ph attribute")
+		}
+	case "sub":
+		err = c.checkAttrs(t, "alias")
+		if err == nil && t.attrs["alias"] == "" {
+			err = c.errorAt(t.offset, "<sub> requires an alias attribute")
+		}
+	case "say-as":
+		err = c.checkAttrs(t, "interpret-as", "format")
+	}
+	if err != nil {
+		return err
+	}
+	if t.selfClosing {
+		return c.errorAt(t.offset, "<%s> must contain text", t.name)
+	}
+	contentAt := c.pos
+	content, err := c.content(t)
+	if err != nil {
+		return err
+	}
+	content = strings.TrimSpace(html.UnescapeString(content))
+
+	switch t.name {
+	case "phoneme":
+		if c.opts.Phonemes {
+			fmt.Fprintf(&c.text, `<phoneme alphabet="%s" ph="%s">%s</phoneme>`, t.attrs["alphabet"], html.EscapeString(t.attrs["ph"]), textEscaper.Replace(content))
+		} else {
+			c.text.WriteString(textEscaper.Replace(content))
+		}
+	case "sub":
+		c.text.WriteString(textEscaper.Replace(t.attrs["alias"]))
+	case "say-as":
+		words, err := sayAs(content, t.attrs["interpret-as"], t.attrs["format"])
+		if err != nil {
+			return c.errorAt(contentAt, "%s", err)
+		}
+		c.text.WriteString(textEscaper.Replace(words))
+	}
+	return nil
+}
+
+// content returns the raw text up to the closing tag of t, which must not contain other tags.
+func (c *converter) content(t tag) (string, error) {
+	start := c.pos
+	end := strings.IndexByte(c.src[c.pos:], '<')
+	if end < 0 {
+		return "", c.errorAt(t.offset, "unclosed <%s>", t.name)
+	}
+	c.pos += end
+	closeAt := c.pos
+	closing, err := c.tag()
+	if err != nil {
+		return "", err
+	}
+	if !closing.closing || closing.name != t.name {
+		return "", c.errorAt(closeAt, "<%s> cannot contain other tags", t.name)
+	}
+	return c.src[start:closeAt], nil
+}
+
+// checkAttrs returns an error if the tag has attributes other than the allowed ones.
+func (c *converter) checkAttrs(t tag, allowed ...string) error {
+	for _, name := range t.order {
+		ok := false
+		for _, a := range allowed {
+			ok = ok || name == a
+		}
+		if !ok {
+			return c.errorAt(t.offset, "unsupported attribute %q of <%s>", name, t.name)
+		}
+	}
+	return nil
+}
+
+type tag struct {
+	name                 string
+	attrs                map[string]string
+	order                []string
+	closing, selfClosing bool
+	offset               int
+}
+
+type parser struct {
+	src string
+	pos int
+}
+
+func (p *parser) errorAt(offset int, format string, args ...interface{}) error {
+	line := 1 + strings.Count(p.src[:offset], "\n")
+	lineStart := strings.LastIndexByte(p.src[:offset], '\n') + 1
+	return &SyntaxError{
+		Offset: offset,
+		Line:   line,
+		Column: 1 + utf8.RuneCountInString(p.src[lineStart:offset]),
+		Msg:    fmt.Sprintf(format, args...),
+	}
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.src) && isSpace(p.src[p.pos]) {
+		p.pos++
+	}
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r'
+}
+
+func isNameChar(r rune) bool {
+	return r == '-' || r == '_' || r == ':' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func (p *parser) name() string {
+	start := p.pos
+	for p.pos < len(p.src) {
+		r, size := utf8.DecodeRuneInString(p.src[p.pos:])
+		if !isNameChar(r) {
+			break
+		}
+		p.pos += size
+	}
+	return p.src[start:p.pos]
+}
+
+// tag parses a start, end or empty element tag at the current position.
+func (p *parser) tag() (tag, error) {
+	t := tag{offset: p.pos, attrs: map[string]string{}}
+	p.pos++ // '<'
+	if p.pos < len(p.src) && p.src[p.pos] == '/' {
+		t.closing = true
+		p.pos++
+	}
+	if t.name = p.name(); t.name == "" {
+		return t, p.errorAt(t.offset, "invalid tag")
+	}
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.src) {
+			return t, p.errorAt(t.offset, "unterminated tag <%s>", t.name)
+		}
+		switch {
+		case p.src[p.pos] == '>':
+			p.pos++
+			return t, nil
+		case strings.HasPrefix(p.src[p.pos:], "/>") && !t.closing:
+			p.pos += 2
+			t.selfClosing = true
+			return t, nil
+		case t.closing:
+			return t, p.errorAt(p.pos, "unexpected character %q in </%s>", p.src[p.pos], t.name)
+		}
+
+		attrAt := p.pos
+		name := p.name()
+		if name == "" {
+			return t, p.errorAt(attrAt, "unexpected character %q in <%s>", p.src[p.pos], t.name)
+		}
+		p.skipSpace()
+		if p.pos >= len(p.src) || p.src[p.pos] != '=' {
+			return t, p.errorAt(attrAt, "attribute %q of <%s> has no value", name, t.name)
+		}
+		p.pos++
+		p.skipSpace()
+		if p.pos >= len(p.src) || (p.src[p.pos] != '"' && p.src[p.pos] != '\'') {
+			return t, p.errorAt(attrAt, "value of attribute %q must be quoted", name)
+		}
+		quote := p.src[p.pos]
+		end := strings.IndexByte(p.src[p.pos+1:], quote)
+		if end < 0 {
+			return t, p.errorAt(attrAt, "unterminated value of attribute %q", name)
+		}
+		if _, dup := t.attrs[name]; dup {
+			return t, p.errorAt(attrAt, "duplicate attribute %q", name)
+		}
+		t.attrs[name] = html.UnescapeString(p.src[p.pos+1 : p.pos+1+end])
+		t.order = append(t.order, name)
+		p.pos += end + 2
+	}
+}
@@ -0,0 +1,118 @@
+package ssml_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/haguro/elevenlabs-go/ssml"
+)
+
+func TestConvert(t *testing.T) {
+	noBreaks := ssml.Options{}
+	tests := []struct {
+		name     string
+		input    string
+		opts     ssml.Options
+		expected []ssml.Segment
+	}{
+		{"PlainText", "Hello &amp; welcome.", ssml.DefaultOptions, []ssml.Segment{{Text: "Hello &amp; welcome."}}},
+		{"EscapedTag", `Say &lt;break time="9s"/&gt; &#60;b&#62; "it's"`, ssml.DefaultOptions,
+			[]ssml.Segment{{Text: `Say &lt;break time="9s"/&gt; &lt;b&gt; "it's"`}}},
+		{"Speak", "<speak>Hello <!-- comment -->world.</speak>", ssml.DefaultOptions, []ssml.Segment{{Text: "Hello world."}}},
+		{"ShortBreak", `One. <break time="500ms"/> Two.`, ssml.DefaultOptions,
+			[]ssml.Segment{{Text: `One. <break time="0.5s" /> Two.`}}},
+		{"StrengthBreak", `One. <break strength="strong"></break> Two.`, ssml.DefaultOptions,
+			[]ssml.Segment{{Text: `One. <break time="1s" /> Two.`}}},
+		{"LongBreak", `One. <break time="5s"/> Two.`, ssml.DefaultOptions,
+			[]ssml.Segment{{Text: "One.", Silence: 5 * time.Second}, {Text: "Two."}}},
+		{"BreaksDisabled", `<break time="1s"/>One. <break time="1s"/><break time="2s"/> Two.`, noBreaks,
+			[]ssml.Segment{{Silence: time.Second}, {Text: "One.", Silence: 3 * time.Second}, {Text: "Two."}}},
+		{"ZeroBreak", `One.<break strength="none"/> Two.`, ssml.DefaultOptions, []ssml.Segment{{Text: "One. Two."}}},
+		{"PhonemeSupported", `Say <phoneme alphabet="cmu-arpabet" ph="M AE1 D IH0 S AH0 N">Madison</phoneme>.`, ssml.OptionsForModel("eleven_turbo_v2"),
+			[]ssml.Segment{{Text: `Say <phoneme alphabet="cmu-arpabet" ph="M AE1 D IH0 S AH0 N">Madison</phoneme>.`}}},
+		{"PhonemeUnsupported", `Say <phoneme alphabet="ipa" ph="ˈæktʃuəli">actually</phoneme>.`, ssml.OptionsForModel("eleven_multilingual_v2"),
+			[]ssml.Segment{{Text: "Say actually."}}},
+		{"Sub", `<sub alias="World Wide Web">WWW</sub> pages`, ssml.DefaultOptions, []ssml.Segment{{Text: "World Wide Web pages"}}},
+		{"EscapedContent", `<sub alias="&lt;break/&gt;">x</sub> <phoneme alphabet="ipa" ph="a">&lt;b&gt;</phoneme>`, ssml.OptionsForModel("eleven_turbo_v2"),
+			[]ssml.Segment{{Text: `&lt;break/&gt; <phoneme alphabet="ipa" ph="a">&lt;b&gt;</phoneme>`}}},
+		{"Cardinal", `<say-as interpret-as="cardinal">-1,234.05</say-as>`, ssml.DefaultOptions,
+			[]ssml.Segment{{Text: "minus one thousand two hundred thirty-four point zero five"}}},
+		{"LargeCardinal", `<say-as interpret-as="number">3000000012</say-as>`, ssml.DefaultOptions,
+			[]ssml.Segment{{Text: "three billion twelve"}}},
+		{"Ordinal", `the <say-as interpret-as="ordinal">22nd</say-as> and <say-as interpret-as="ordinal">40</say-as>`, ssml.DefaultOptions,
+			[]ssml.Segment{{Text: "the twenty-second and fortieth"}}},
+		{"Digits", `<say-as interpret-as="digits">2024</say-as>`, ssml.DefaultOptions, []ssml.Segment{{Text: "two zero two four"}}},
+		{"Characters", `<say-as interpret-as="characters">AB1</say-as>`, ssml.DefaultOptions, []ssml.Segment{{Text: "A B one"}}},
+		{"Telephone", `<say-as interpret-as="telephone">+1 (555) 0123</say-as>`, ssml.DefaultOptions,
+			[]ssml.Segment{{Text: "plus one, five five five, zero one two three"}}},
+		{"Date", `<say-as interpret-as="date">12/25/2024</say-as>`, ssml.DefaultOptions,
+			[]ssml.Segment{{Text: "December twenty-fifth, twenty twenty-four"}}},
+		{"DateFormat", `<say-as interpret-as="date" format="dmy">5.3.1905</say-as>`, ssml.DefaultOptions,
+			[]ssml.Segment{{Text: "March fifth, nineteen oh five"}}},
+		{"YearOnly", `<say-as interpret-as="date" format="y">2008</say-as>`, ssml.DefaultOptions,
+			[]ssml.Segment{{Text: "two thousand eight"}}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			segments, err := ssml.Convert(tc.input, tc.opts)
+			if err != nil {
+				t.Fatalf("Expected no errors, got %q", err)
+			}
+			if !reflect.DeepEqual(segments, tc.expected) {
+				t.Errorf("Expected segments %q, got %q", tc.expected, segments)
+			}
+		})
+	}
+}
+
+func TestConvertErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected ssml.SyntaxError
+	}{
+		{"UnsupportedTag", "Hello\n  <emphasis>there</emphasis>", ssml.SyntaxError{Offset: 8, Line: 2, Column: 3, Msg: "unsupported tag <emphasis>"}},
+		{"UnescapedLessThan", "1 < 2", ssml.SyntaxError{Offset: 2, Line: 1, Column: 3, Msg: "invalid tag"}},
+		{"BadBreakTime", `é <break time="soon"/>`, ssml.SyntaxError{Offset: 3, Line: 1, Column: 3, Msg: `invalid break time "soon"`}},
+		{"UnknownAttribute", `<break time="1s" level="2"/>`, ssml.SyntaxError{Offset: 0, Line: 1, Column: 1, Msg: `unsupported attribute "level" of <break>`}},
+		{"UnquotedAttribute", `<break time=1s/>`, ssml.SyntaxError{Offset: 7, Line: 1, Column: 8, Msg: `value of attribute "time" must be quoted`}},
+		{"BreakWithText", `One. <break time="1s">Two.</break>`, ssml.SyntaxError{Offset: 5, Line: 1, Column: 6, Msg: "<break> cannot contain text"}},
+		{"NestedTags", `<sub alias="x">a<break/></sub>`, ssml.SyntaxError{Offset: 16, Line: 1, Column: 17, Msg: "<sub> cannot contain other tags"}},
+		{"UnclosedContent", `<sub alias="x">abc`, ssml.SyntaxError{Offset: 0, Line: 1, Column: 1, Msg: "unclosed <sub>"}},
+		{"UnclosedSpeak", "<speak>\nHello", ssml.SyntaxError{Offset: 0, Line: 1, Column: 1, Msg: "unclosed <speak>"}},
+		{"UnexpectedClose", "Hello</sub>", ssml.SyntaxError{Offset: 5, Line: 1, Column: 6, Msg: "unexpected </sub>"}},
+		{"MissingAlias", `<sub>WWW</sub>`, ssml.SyntaxError{Offset: 0, Line: 1, Column: 1, Msg: "<sub> requires an alias attribute"}},
+		{"BadAlphabet", `<phoneme alphabet="x-sampa" ph="a">a</phoneme>`, ssml.SyntaxError{Offset: 0, Line: 1, Column: 1, Msg: `unsupported phoneme alphabet "x-sampa"`}},
+		{"BadNumber", "Total:\n<say-as interpret-as=\"cardinal\">12a</say-as>", ssml.SyntaxError{Offset: 39, Line: 2, Column: 33, Msg: `invalid number "12a"`}},
+		{"BadDate", `<say-as interpret-as="date">13/01/2024</say-as>`, ssml.SyntaxError{Offset: 28, Line: 1, Column: 29, Msg: `invalid date "13/01/2024"`}},
+		{"UnknownInterpretAs", `<say-as interpret-as="currency">$5</say-as>`, ssml.SyntaxError{Offset: 32, Line: 1, Column: 33, Msg: `unsupported interpret-as "currency"`}},
+		{"UnterminatedComment", "a <!-- b", ssml.SyntaxError{Offset: 2, Line: 1, Column: 3, Msg: "unterminated comment"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ssml.Convert(tc.input, ssml.DefaultOptions)
+			var synErr *ssml.SyntaxError
+			if !errors.As(err, &synErr) {
+				t.Fatalf("Expected a SyntaxError, got %v", err)
+			}
+			if *synErr != tc.expected {
+				t.Errorf("Expected error %+v, got %+v", tc.expected, *synErr)
+			}
+		})
+	}
+}
+
+func TestText(t *testing.T) {
+	text, err := ssml.Text(`Hi <break time="1s"/> there`, ssml.DefaultOptions)
+	if err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+	if text != `Hi <break time="1s" /> there` {
+		t.Errorf("Unexpected text %q", text)
+	}
+	if _, err := ssml.Text(`Hi <break time="10s"/> there`, ssml.DefaultOptions); err == nil {
+		t.Errorf("Expected an error for text that requires several segments")
+	}
+}
@@ -0,0 +1,505 @@
+package textnorm
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// English is the Normalizer of English. It expands currencies, dates, times, units, ordinals, numbers and
+// common abbreviations, e.g. "Dr. Lee paid $5.50 on 3/14/2024 at 9:05 pm" becomes "Doctor Lee paid five dollars
+// and fifty cents on March fourteenth, twenty twenty-four at nine oh five PM".
+type English struct {
+	// British selects British conventions: day first numeric dates, "the fifth of March", "one hundred and
+	// five" and metric units spelled "metre" and "litre".
+	British bool
+}
+
+var (
+	smallNumbers = []string{"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine", "ten",
+		"eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen", "seventeen", "eighteen", "nineteen"}
+	tens              = []string{"", "", "twenty", "thirty", "forty", "fifty", "sixty", "seventy", "eighty", "ninety"}
+	scales            = []string{"", "thousand", "million", "billion", "trillion", "quadrillion", "quintillion"}
+	irregularOrdinals = map[string]string{"one": "first", "two": "second", "three": "third", "five": "fifth",
+		"eight": "eighth", "nine": "ninth", "twelve": "twelfth"}
+	months = []string{"January", "February", "March", "April", "May", "June", "July", "August", "September",
+		"October", "November", "December"}
+)
+
+// Cardinal returns the words for an integer, e.g. "one thousand two hundred thirty-four".
+func (e English) Cardinal(n int64) string {
+	if n < 0 {
+		// The magnitude of the smallest int64 does not fit in an int64.
+		return "minus " + e.cardinal(uint64(-(n+1))+1)
+	}
+	return e.cardinal(uint64(n))
+}
+
+func (e English) cardinal(n uint64) string {
+	if n < 20 {
+		return smallNumbers[n]
+	}
+	if n < 100 {
+		if n%10 == 0 {
+			return tens[n/10]
+		}
+		return tens[n/10] + "-" + smallNumbers[n%10]
+	}
+	if n < 1000 {
+		words := smallNumbers[n/100] + " hundred"
+		switch {
+		case n%100 == 0:
+		case e.British:
+			words += " and " + e.cardinal(n%100)
+		default:
+			words += " " + e.cardinal(n%100)
+		}
+		return words
+	}
+	var groups []string
+	last := n % 1000
+	for scale := 0; n > 0; scale++ {
+		if g := n % 1000; g != 0 {
+			words := e.cardinal(g)
+			if scales[scale] != "" {
+				words += " " + scales[scale]
+			}
+			groups = append([]string{words}, groups...)
+		}
+		n /= 1000
+	}
+	if e.British && last > 0 && last < 100 && len(groups) > 1 {
+		return strings.Join(groups[:len(groups)-1], " ") + " and " + groups[len(groups)-1]
+	}
+	return strings.Join(groups, " ")
+}
+
+// Ordinal returns the ordinal words for an integer, e.g. "twenty-first".
+func (e English) Ordinal(n int64) string {
+	words := e.Cardinal(n)
+	i := strings.LastIndexAny(words, " -") + 1
+	last := words[i:]
+	switch {
+	case irregularOrdinals[last] != "":
+		last = irregularOrdinals[last]
+	case strings.HasSuffix(last, "y"):
+		last = strings.TrimSuffix(last, "y") + "ieth"
+	default:
+		last += "th"
+	}
+	return words[:i] + last
+}
+
+// Year returns a year the way it is usually read, e.g. "nineteen eighty-four", "nineteen oh five" or "two
+// thousand five".
+func (e English) Year(y int64) string {
+	switch {
+	case y < 1000 || y >= 10000 || (y >= 2000 && y < 2010) || y%1000 == 0:
+		return e.Cardinal(y)
+	case y%100 == 0:
+		return e.Cardinal(y/100) + " hundred"
+	case y%100 < 10:
+		return e.Cardinal(y/100) + " oh " + e.Cardinal(y%100)
+	}
+	return e.Cardinal(y/100) + " " + e.Cardinal(y%100)
+}
+
+// Date returns the words for a date, e.g. "March fifth, twenty twenty-four" or, with British conventions,
+// "the fifth of March, twenty twenty-four". A zero year, month or day is left out.
+func (e English) Date(year, month, day int) string {
+	var words string
+	switch {
+	case month > 0 && day > 0 && e.British:
+		words = "the " + e.Ordinal(int64(day)) + " of " + months[month-1]
+	case month > 0 && day > 0:
+		words = months[month-1] + " " + e.Ordinal(int64(day))
+	case month > 0:
+		words = months[month-1]
+	case day > 0:
+		words = "the " + e.Ordinal(int64(day))
+	}
+	if year > 0 {
+		switch {
+		case day > 0:
+			words += ", "
+		case words != "":
+			words += " "
+		}
+		words += e.Year(int64(year))
+	}
+	return words
+}
+
+// Number returns the words for a number such as "-1,234.05", and a bool that is false if s is not a number.
+// Digits after the decimal point are read one by one.
+func (e English) Number(s string) (string, bool) {
+	num := strings.ReplaceAll(s, ",", "")
+	var words []string
+	if strings.HasPrefix(num, "-") {
+		words = append(words, "minus")
+		num = num[1:]
+	}
+	intPart, fracPart, hasFrac := strings.Cut(num, ".")
+	if intPart == "" || (hasFrac && fracPart == "") || !isDigits(intPart) || !isDigits(fracPart) {
+		return "", false
+	}
+	if n, err := strconv.ParseInt(intPart, 10, 64); err == nil {
+		words = append(words, e.Cardinal(n))
+	} else {
+		words = append(words, digitWords(intPart))
+	}
+	if hasFrac {
+		words = append(words, "point", digitWords(fracPart))
+	}
+	return strings.Join(words, " "), true
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// digitWords reads a string of digits one digit at a time.
+func digitWords(digits string) string {
+	words := make([]string, len(digits))
+	for i := 0; i < len(digits); i++ {
+		words[i] = smallNumbers[digits[i]-'0']
+	}
+	return strings.Join(words, " ")
+}
+
+const (
+	numberPattern = `(?:\d{1,3}(?:,\d{3})+|\d+)(?:\.\d+)?`
+	monthPattern  = `January|February|March|April|May|June|July|August|September|October|November|December|` +
+		`Jan|Feb|Mar|Apr|Jun|Jul|Aug|Sept|Sep|Oct|Nov|Dec`
+)
+
+var (
+	abbreviationRe = regexp.MustCompile(`\b(Dr|Mr|Mrs|Ms|Prof|Jr|Sr|Mt|St|vs|etc|approx|No|e\.g|i\.e)\.(\s*)`)
+	currencyRe     = regexp.MustCompile(`([$€£¥])\s?(` + numberPattern + `)(?:\s?(thousand|million|billion|trillion|bn|k|m)\b)?`)
+	isoDateRe      = regexp.MustCompile(`\b(\d{4})-(\d{1,2})-(\d{1,2})\b`)
+	slashDateRe    = regexp.MustCompile(`\b(\d{1,2})/(\d{1,2})/(\d{4})\b`)
+	monthDayRe     = regexp.MustCompile(`\b(` + monthPattern + `)\.?\s+(\d{1,2})(?:st|nd|rd|th)?\b(?:,?\s+(\d{4})\b)?`)
+	dayMonthRe     = regexp.MustCompile(`\b(\d{1,2})(?:st|nd|rd|th)?\s+(` + monthPattern + `)\b\.?(?:,?\s+(\d{4})\b)?`)
+	timeRe         = regexp.MustCompile(`\b(\d{1,2})(?::(\d{2}))?(?:\s?([AaPp])(?:\.[Mm]\.|[Mm]\b))|\b(\d{1,2}):(\d{2})\b`)
+	unitRe         = regexp.MustCompile(`(-?` + numberPattern + `)\s?(?:(%)|(km/h|kph|mph|km|cm|mm|mi|kg|mg|lbs|lb|ft|kWh|kW|GB|MB|KB|TB|ms|°C|°F|m|g|l|ml)\b)`)
+	ordinalRe      = regexp.MustCompile(`\b(\d+)(?:st|nd|rd|th)\b`)
+	numberRe       = regexp.MustCompile(`-?` + numberPattern)
+)
+
+var abbreviations = map[string]string{
+	"Dr": "Doctor", "Mr": "Mister", "Mrs": "Missus", "Ms": "Miz", "Prof": "Professor", "Jr": "Junior",
+	"Sr": "Senior", "Mt": "Mount", "vs": "versus", "etc": "et cetera", "approx": "approximately",
+	"e.g": "for example", "i.e": "that is",
+}
+
+var currencyNames = map[string][4]string{
+	"$": {"dollar", "dollars", "cent", "cents"},
+	"€": {"euro", "euros", "cent", "cents"},
+	"£": {"pound", "pounds", "penny", "pence"},
+	"¥": {"yen", "yen", "", ""},
+}
+
+var currencyScales = map[string]string{"k": "thousand", "m": "million", "bn": "billion"}
+
+// unitNames holds the singular and plural of units. Metric units ending in "meter" and "liter" are respelled
+// for British English.
+var unitNames = map[string][2]string{
+	"km/h": {"kilometer per hour", "kilometers per hour"}, "kph": {"kilometer per hour", "kilometers per hour"},
+	"mph": {"mile per hour", "miles per hour"}, "km": {"kilometer", "kilometers"}, "m": {"meter", "meters"},
+	"cm": {"centimeter", "centimeters"}, "mm": {"millimeter", "millimeters"}, "mi": {"mile", "miles"},
+	"kg": {"kilogram", "kilograms"}, "g": {"gram", "grams"}, "mg": {"milligram", "milligrams"},
+	"lb": {"pound", "pounds"}, "lbs": {"pound", "pounds"}, "ft": {"foot", "feet"},
+	"kWh": {"kilowatt hour", "kilowatt hours"}, "kW": {"kilowatt", "kilowatts"},
+	"KB": {"kilobyte", "kilobytes"}, "MB": {"megabyte", "megabytes"}, "GB": {"gigabyte", "gigabytes"},
+	"TB": {"terabyte", "terabytes"}, "ms": {"millisecond", "milliseconds"},
+	"°C": {"degree Celsius", "degrees Celsius"}, "°F": {"degree Fahrenheit", "degrees Fahrenheit"},
+	"l": {"liter", "liters"}, "ml": {"milliliter", "milliliters"}, "%": {"percent", "percent"},
+}
+
+// replaceFunc replaces the matches of re in s with the result of f, which is given the submatches of the match
+// (empty for groups that did not participate) and its start offset. Matches for which f returns false are
+// left unchanged.
+func replaceFunc(re *regexp.Regexp, s string, f func(m []string, start int) (string, bool)) string {
+	var b strings.Builder
+	last := 0
+	for _, idx := range re.FindAllStringSubmatchIndex(s, -1) {
+		m := make([]string, len(idx)/2)
+		for i := range m {
+			if idx[2*i] >= 0 {
+				m[i] = s[idx[2*i]:idx[2*i+1]]
+			}
+		}
+		repl, ok := f(m, idx[0])
+		if !ok {
+			continue
+		}
+		b.WriteString(s[last:idx[0]])
+		b.WriteString(repl)
+		last = idx[1]
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// date returns the words for a date, and a bool that is false if the month or day is out of range.
+func (e English) date(year, month, day int) (string, bool) {
+	if month < 1 || month > 12 || day < 1 || day > daysIn(month, year) {
+		return "", false
+	}
+	return e.Date(year, month, day), true
+}
+
+// daysIn returns the number of days of a month. February has 29 days in leap years and when the year is 0,
+// i.e. unknown.
+func daysIn(month, year int) int {
+	switch month {
+	case 2:
+		if year == 0 || (year%4 == 0 && (year%100 != 0 || year%400 == 0)) {
+			return 29
+		}
+		return 28
+	case 4, 6, 9, 11:
+		return 30
+	}
+	return 31
+}
+
+// monthNumber returns the number of a month given its name or abbreviation.
+func monthNumber(name string) int {
+	for i, m := range months {
+		if strings.HasPrefix(m, name) {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// Normalize expands the currencies, dates, times, units, ordinals, numbers and abbreviations of an English text
+// into words. Digits that are part of identifiers, versions, paths and the like, e.g. "mp3" or "v1.2.3", and
+// impossible dates such as "Feb 30" are not read as numbers or dates.
+func (e English) Normalize(text string) string {
+	text = replaceFunc(abbreviationRe, text, func(m []string, start int) (string, bool) {
+		abbr, space := m[1], m[2]
+		next, _ := utf8.DecodeRuneInString(text[start+len(m[0]):])
+		var words string
+		switch abbr {
+		case "No":
+			if !unicode.IsDigit(next) {
+				return "", false
+			}
+			words = "number"
+		case "St":
+			words = "Street"
+			if space != "" && unicode.IsUpper(next) {
+				words = "Saint"
+			}
+		default:
+			words = abbreviations[abbr]
+		}
+		if space == "" || strings.Contains(space, "\n") {
+			// The period also ended the sentence.
+			words += "."
+		}
+		return words + space, true
+	})
+
+	text = replaceFunc(currencyRe, text, func(m []string, start int) (string, bool) {
+		if !separatedAfter(text[start+len(m[0]):]) {
+			return "", false
+		}
+		names := currencyNames[m[1]]
+		if m[3] != "" {
+			amount, _ := e.Number(m[2])
+			scale := m[3]
+			if s, ok := currencyScales[scale]; ok {
+				scale = s
+			}
+			return amount + " " + scale + " " + names[1], true
+		}
+		whole, frac, _ := strings.Cut(strings.ReplaceAll(m[2], ",", ""), ".")
+		n, err := strconv.ParseInt(whole, 10, 64)
+		if err != nil || len(frac) > 2 || (frac != "" && names[2] == "") {
+			amount, _ := e.Number(m[2])
+			return amount + " " + names[1], true
+		}
+		var cents int64
+		if frac != "" {
+			cents, _ = strconv.ParseInt((frac + "0")[:2], 10, 64)
+		}
+		var parts []string
+		if n > 0 || cents == 0 {
+			unit := names[1]
+			if n == 1 {
+				unit = names[0]
+			}
+			parts = append(parts, e.Cardinal(n)+" "+unit)
+		}
+		if cents > 0 {
+			unit := names[3]
+			if cents == 1 {
+				unit = names[2]
+			}
+			parts = append(parts, e.Cardinal(cents)+" "+unit)
+		}
+		return strings.Join(parts, " and "), true
+	})
+
+	text = replaceFunc(isoDateRe, text, func(m []string, _ int) (string, bool) {
+		year, month, day := atoi(m[1]), atoi(m[2]), atoi(m[3])
+		return e.date(year, month, day)
+	})
+	text = replaceFunc(slashDateRe, text, func(m []string, _ int) (string, bool) {
+		month, day, year := atoi(m[1]), atoi(m[2]), atoi(m[3])
+		if e.British {
+			month, day = day, month
+		}
+		return e.date(year, month, day)
+	})
+	text = replaceFunc(monthDayRe, text, func(m []string, _ int) (string, bool) {
+		year, month, day := atoi(m[3]), monthNumber(m[1]), atoi(m[2])
+		return e.date(year, month, day)
+	})
+	text = replaceFunc(dayMonthRe, text, func(m []string, _ int) (string, bool) {
+		year, month, day := atoi(m[3]), monthNumber(m[2]), atoi(m[1])
+		return e.date(year, month, day)
+	})
+
+	text = replaceFunc(timeRe, text, func(m []string, _ int) (string, bool) {
+		if m[4] != "" {
+			hour, min := atoi(m[4]), atoi(m[5])
+			if hour > 23 || min > 59 {
+				return "", false
+			}
+			switch {
+			case min == 0 && hour <= 12:
+				return e.Cardinal(int64(hour)) + " o'clock", true
+			case min == 0:
+				return e.Cardinal(int64(hour)) + " hundred", true
+			}
+			return e.Cardinal(int64(hour)) + " " + e.minutes(min), true
+		}
+		hour, min := atoi(m[1]), atoi(m[2])
+		if hour < 1 || hour > 12 || min > 59 {
+			return "", false
+		}
+		words := e.Cardinal(int64(hour))
+		if min > 0 {
+			words += " " + e.minutes(min)
+		}
+		return words + " " + strings.ToUpper(m[3]) + "M", true
+	})
+
+	text = replaceFunc(unitRe, text, func(m []string, start int) (string, bool) {
+		num, prefix := m[1], ""
+		if strings.HasPrefix(num, "-") && isHyphen(text, start) {
+			num, prefix = num[1:], "-"
+		} else if !separatedBefore(text[:start]) {
+			return "", false
+		}
+		amount, _ := e.Number(num)
+		unit := m[2] + m[3]
+		names := unitNames[unit]
+		name := names[1]
+		if num == "1" {
+			name = names[0]
+		}
+		if e.British {
+			name = strings.ReplaceAll(strings.ReplaceAll(name, "meter", "metre"), "liter", "litre")
+		}
+		return prefix + amount + " " + name, true
+	})
+
+	text = replaceFunc(ordinalRe, text, func(m []string, _ int) (string, bool) {
+		n, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil {
+			return "", false
+		}
+		return e.Ordinal(n), true
+	})
+
+	return replaceFunc(numberRe, text, func(m []string, start int) (string, bool) {
+		num, prefix := m[0], ""
+		if strings.HasPrefix(num, "-") && isHyphen(text, start) {
+			num, prefix = num[1:], "-"
+		} else if !separatedBefore(text[:start]) {
+			return "", false
+		}
+		if !separatedAfter(text[start+len(m[0]):]) {
+			return "", false
+		}
+		if len(num) == 4 && isDigits(num) {
+			if y := atoi(num); y >= 1100 && y < 2100 {
+				return prefix + e.Year(int64(y)), true
+			}
+		}
+		words, ok := e.Number(num)
+		return prefix + words, ok
+	})
+}
+
+// isHyphen reports whether the "-" at offset i of text is a hyphen between words or numbers, as in "COVID-19",
+// rather than a minus sign.
+func isHyphen(text string, i int) bool {
+	r, _ := utf8.DecodeLastRuneInString(text[:i])
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// separatedBefore reports whether a number that follows s starts a token of its own, rather than continuing an
+// identifier such as "A4", a version such as "1.2.3", a path such as "example.com/page/2" or a number with misplaced digit group commas such as
+// "1,23".
+func separatedBefore(s string) bool {
+	r, size := utf8.DecodeLastRuneInString(s)
+	if isWordRune(r) {
+		return false
+	}
+	prev, _ := utf8.DecodeLastRuneInString(s[:len(s)-size])
+	switch r {
+	case '.', '/':
+		return !isWordRune(prev)
+	case ',':
+		return !unicode.IsDigit(prev)
+	}
+	return true
+}
+
+// separatedAfter reports whether a number that precedes s ends a token of its own, rather than starting an
+// identifier such as "3D", a version such as "1.2.3", a path such as "2/page" or a number with misplaced digit group commas such as
+// "1,23".
+func separatedAfter(s string) bool {
+	r, size := utf8.DecodeRuneInString(s)
+	if isWordRune(r) {
+		return false
+	}
+	next, _ := utf8.DecodeRuneInString(s[size:])
+	switch r {
+	case '.', ',':
+		return !unicode.IsDigit(next)
+	case '/':
+		return !isWordRune(next)
+	}
+	return true
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// minutes returns the words for the minutes of a time, e.g. "oh five" or "forty-five".
+func (e English) minutes(min int) string {
+	if min < 10 {
+		return "oh " + e.Cardinal(int64(min))
+	}
+	return e.Cardinal(int64(min))
+}
@@ -0,0 +1,113 @@
+package textnorm_test
+
+import (
+	"testing"
+
+	"github.com/haguro/elevenlabs-go/textnorm"
+)
+
+func TestEnglishWords(t *testing.T) {
+	us, uk := textnorm.English{}, textnorm.English{British: true}
+	tests := []struct {
+		name     string
+		got      string
+		expected string
+	}{
+		{"Cardinal", us.Cardinal(1234), "one thousand two hundred thirty-four"},
+		{"CardinalBritish", uk.Cardinal(1234), "one thousand two hundred and thirty-four"},
+		{"CardinalBritishTens", uk.Cardinal(3000005), "three million and five"},
+		{"CardinalNegative", us.Cardinal(-40), "minus forty"},
+		{"CardinalMinInt", us.Cardinal(-9223372036854775808), "minus nine quintillion two hundred twenty-three quadrillion three hundred seventy-two trillion thirty-six billion eight hundred fifty-four million seven hundred seventy-five thousand eight hundred eight"},
+		{"Ordinal", us.Ordinal(21), "twenty-first"},
+		{"OrdinalTens", us.Ordinal(90), "ninetieth"},
+		{"OrdinalHundred", uk.Ordinal(112), "one hundred and twelfth"},
+		{"Year", us.Year(1984), "nineteen eighty-four"},
+		{"YearOh", us.Year(1905), "nineteen oh five"},
+		{"YearHundred", us.Year(1900), "nineteen hundred"},
+		{"YearTwoThousand", us.Year(2008), "two thousand eight"},
+		{"Date", us.Date(2024, 12, 25), "December twenty-fifth, twenty twenty-four"},
+		{"DateBritish", uk.Date(2024, 12, 25), "the twenty-fifth of December, twenty twenty-four"},
+		{"DateNoYear", us.Date(0, 3, 5), "March fifth"},
+		{"DateNoDay", us.Date(1999, 3, 0), "March nineteen ninety-nine"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.got != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, tc.got)
+			}
+		})
+	}
+}
+
+func TestEnglishNumber(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		ok       bool
+	}{
+		{"-1,234.05", "minus one thousand two hundred thirty-four point zero five", true},
+		{"0.5", "zero point five", true},
+		{"12345678901234567890", "one two three four five six seven eight nine zero one two three four five six seven eight nine zero", true},
+		{"12a", "", false},
+		{"1.", "", false},
+		{"", "", false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			words, ok := textnorm.English{}.Number(tc.input)
+			if words != tc.expected || ok != tc.ok {
+				t.Errorf("Expected %q, %t, got %q, %t", tc.expected, tc.ok, words, ok)
+			}
+		})
+	}
+}
+
+func TestEnglishNormalize(t *testing.T) {
+	tests := []struct {
+		name     string
+		british  bool
+		input    string
+		expected string
+	}{
+		{"Abbreviations", false, "Dr. Lee met Mr. Smith at St. Paul's vs. Main St.", "Doctor Lee met Mister Smith at Saint Paul's versus Main Street."},
+		{"NumberAbbreviation", false, "See No. 5, not No way.", "See number five, not No way."},
+		{"Latin", false, "Fruit, e.g. apples, etc.", "Fruit, for example apples, et cetera."},
+		{"Currency", false, "It costs $5.50 or $1.", "It costs five dollars and fifty cents or one dollar."},
+		{"CurrencyPence", false, "£1.01 and €20", "one pound and one penny and twenty euros"},
+		{"CurrencyCents", false, "$0.99", "ninety-nine cents"},
+		{"CurrencyScale", false, "$3.5m and £2 billion", "three point five million dollars and two billion pounds"},
+		{"Yen", false, "¥1,500", "one thousand five hundred yen"},
+		{"ISODate", false, "On 2024-12-25.", "On December twenty-fifth, twenty twenty-four."},
+		{"SlashDate", false, "On 3/14/2024.", "On March fourteenth, twenty twenty-four."},
+		{"SlashDateBritish", true, "On 14/3/2024.", "On the fourteenth of March, twenty twenty-four."},
+		{"MonthDay", false, "March 5, 1905 and Jan. 3rd", "March fifth, nineteen oh five and January third"},
+		{"SlashDateInvalid", true, "On 3/14/2024.", "On 3/14/2024."},
+		{"DayMonth", true, "5 March 2024", "the fifth of March, twenty twenty-four"},
+		{"Time", false, "At 9:05 pm or 11am.", "At nine oh five PM or eleven AM."},
+		{"Time24", false, "From 14:30 to 7:00.", "From fourteen thirty to seven o'clock."},
+		{"NotTime", false, "5 amazing days", "five amazing days"},
+		{"Units", false, "Run 5 km at 12 mph with 1 kg.", "Run five kilometers at twelve miles per hour with one kilogram."},
+		{"UnitsBritish", true, "1.5 m and 2 l", "one point five metres and two litres"},
+		{"Percent", false, "50% off", "fifty percent off"},
+		{"Temperature", false, "It is -5 °C.", "It is minus five degrees Celsius."},
+		{"Ordinals", true, "The 1st, 22nd and 103rd.", "The first, twenty-second and one hundred and third."},
+		{"Years", false, "In 1984 and 2024, not 2500.", "In nineteen eighty-four and twenty twenty-four, not two thousand five hundred."},
+		{"Numbers", false, "1,234,567 and 3.14", "one million two hundred thirty-four thousand five hundred sixty-seven and three point one four"},
+		{"Hyphen", false, "COVID-19 and -3", "COVID-nineteen and minus three"},
+		{"Identifiers", false, "A4 paper, an mp3 file and 3D", "A4 paper, an mp3 file and 3D"},
+		{"Versions", false, "Upgrade from v1.2.3 to 1.2.4.", "Upgrade from v1.2.3 to 1.2.4."},
+		{"URL", false, "See example.com/page2 or go.dev/1.21", "See example.com/page2 or go.dev/1.21"},
+		{"MisplacedCommas", false, "1,23 and $1,23 but 1,234", "1,23 and $1,23 but one thousand two hundred thirty-four"},
+		{"InvalidDate", false, "Feb 30", "Feb thirty"},
+		{"LeapDay", false, "2024-02-29 but not Feb 29, 2023", "February twenty-ninth, twenty twenty-four but not Feb twenty-nine, twenty twenty-three"},
+		{"Untouched", false, "Nothing to do here.", "Nothing to do here."},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := textnorm.English{British: tc.british}.Normalize(tc.input)
+			if got != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
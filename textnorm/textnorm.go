@@ -0,0 +1,102 @@
+// Package textnorm normalizes text before it is sent to the text to speech endpoints, expanding numbers,
+// dates, currencies and the like into words.
+//
+// The API normalizes text itself, except with the strongest latency optimizations (see
+// elevenlabs.LatencyOptimizations), which turn its normalizer off and cause numbers and dates to be
+// mispronounced. Normalizing text locally keeps the pronunciation right at the lowest latency.
+//
+// Normalizers are registered per language. English is built in, and other languages can be added with Register.
+package textnorm
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/haguro/elevenlabs-go"
+)
+
+// ErrUnsupportedLanguage is returned when no normalizer is registered for a language.
+var ErrUnsupportedLanguage = errors.New("textnorm: unsupported language")
+
+// Normalizer expands the numbers, dates, abbreviations and the like of a text into words.
+type Normalizer interface {
+	Normalize(text string) string
+}
+
+var (
+	mu          sync.RWMutex
+	normalizers = map[string]Normalizer{
+		"en":    English{},
+		"en-us": English{},
+		"en-gb": English{British: true},
+		"en-au": English{British: true},
+		"en-ie": English{British: true},
+		"en-nz": English{British: true},
+	}
+)
+
+func languageKey(lang string) string {
+	return strings.ToLower(strings.ReplaceAll(lang, "_", "-"))
+}
+
+// Register registers the normalizer of a language, given as a language tag such as "de" or "pt-BR". Lookups for
+// a tag with a region fall back to the normalizer of its language, so registering "de" covers "de-AT" too.
+// Registering a language again replaces its normalizer.
+func Register(lang string, n Normalizer) {
+	mu.Lock()
+	normalizers[languageKey(lang)] = n
+	mu.Unlock()
+}
+
+// Lookup returns the normalizer of a language, given as a language tag, and a bool that is false if there is
+// none.
+func Lookup(lang string) (Normalizer, bool) {
+	key := languageKey(lang)
+	mu.RLock()
+	defer mu.RUnlock()
+	if n, ok := normalizers[key]; ok {
+		return n, true
+	}
+	if i := strings.IndexByte(key, '-'); i > 0 {
+		n, ok := normalizers[key[:i]]
+		return n, ok
+	}
+	return nil, false
+}
+
+// Normalize normalizes a text with the normalizer of a language.
+//
+// It returns the normalized text, or an error wrapping ErrUnsupportedLanguage if no normalizer is registered
+// for the language.
+func Normalize(lang, text string) (string, error) {
+	n, ok := Lookup(lang)
+	if !ok {
+		return "", fmt.Errorf("%w %q", ErrUnsupportedLanguage, lang)
+	}
+	return n.Normalize(text), nil
+}
+
+// NormalizeRequest normalizes the Text, PreviousText and NextText of a text to speech request and turns the
+// normalization of the API off, typically before sending the request with maximum latency optimizations.
+//
+// It takes a TextToSpeechRequest argument and a string argument that represents the language used when the
+// request has no LanguageCode.
+//
+// It returns the normalized request, or an error wrapping ErrUnsupportedLanguage if no normalizer is
+// registered for the language.
+func NormalizeRequest(ttsReq elevenlabs.TextToSpeechRequest, lang string) (elevenlabs.TextToSpeechRequest, error) {
+	if ttsReq.LanguageCode != "" {
+		lang = ttsReq.LanguageCode
+	}
+	n, ok := Lookup(lang)
+	if !ok {
+		return ttsReq, fmt.Errorf("%w %q", ErrUnsupportedLanguage, lang)
+	}
+	ttsReq.Text = n.Normalize(ttsReq.Text)
+	ttsReq.PreviousText = n.Normalize(ttsReq.PreviousText)
+	ttsReq.NextText = n.Normalize(ttsReq.NextText)
+	ttsReq.ApplyTextNormalization = elevenlabs.TextNormalizationOff
+	return ttsReq, nil
+}
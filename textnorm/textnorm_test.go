@@ -0,0 +1,83 @@
+package textnorm_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/haguro/elevenlabs-go"
+	"github.com/haguro/elevenlabs-go/textnorm"
+)
+
+type upperNormalizer struct{}
+
+func (upperNormalizer) Normalize(text string) string {
+	return strings.ToUpper(text)
+}
+
+func TestLookup(t *testing.T) {
+	textnorm.Register("xx", upperNormalizer{})
+	tests := []struct {
+		lang     string
+		expected textnorm.Normalizer
+		ok       bool
+	}{
+		{"en", textnorm.English{}, true},
+		{"en-GB", textnorm.English{British: true}, true},
+		{"en_AU", textnorm.English{British: true}, true},
+		{"en-CA", textnorm.English{}, true},
+		{"xx-YY", upperNormalizer{}, true},
+		{"zz", nil, false},
+		{"", nil, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.lang, func(t *testing.T) {
+			n, ok := textnorm.Lookup(tc.lang)
+			if n != tc.expected || ok != tc.ok {
+				t.Errorf("Expected %v, %t, got %v, %t", tc.expected, tc.ok, n, ok)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	text, err := textnorm.Normalize("en-US", "Chapter 3")
+	if err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+	if text != "Chapter three" {
+		t.Errorf("Expected %q, got %q", "Chapter three", text)
+	}
+	if _, err := textnorm.Normalize("zz", "Chapter 3"); !errors.Is(err, textnorm.ErrUnsupportedLanguage) {
+		t.Errorf("Expected error %v, got %v", textnorm.ErrUnsupportedLanguage, err)
+	}
+}
+
+func TestNormalizeRequest(t *testing.T) {
+	textnorm.Register("xx", upperNormalizer{})
+	ttsReq := elevenlabs.TextToSpeechRequest{Text: "Chapter 3", PreviousText: "Part 2.", ModelID: "model1"}
+	got, err := textnorm.NormalizeRequest(ttsReq, "en")
+	if err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+	expected := elevenlabs.TextToSpeechRequest{Text: "Chapter three", PreviousText: "Part two.", ModelID: "model1",
+		ApplyTextNormalization: elevenlabs.TextNormalizationOff}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected request %+v, got %+v", expected, got)
+	}
+
+	ttsReq.LanguageCode = "xx"
+	got, err = textnorm.NormalizeRequest(ttsReq, "en")
+	if err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+	if got.Text != "CHAPTER 3" {
+		t.Errorf("Expected the normalizer of the request's language code to be used, got text %q", got.Text)
+	}
+
+	ttsReq.LanguageCode = "zz"
+	if _, err := textnorm.NormalizeRequest(ttsReq, "en"); !errors.Is(err, textnorm.ErrUnsupportedLanguage) {
+		t.Errorf("Expected error %v, got %v", textnorm.ErrUnsupportedLanguage, err)
+	}
+}
@@ -0,0 +1,217 @@
+package elevenlabs
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// TextToSpeechRecord describes a text to speech request in enough detail to regenerate the same audio later
+// with Replay. It is returned by RecordedTextToSpeech and RecordedTextToSpeechStream and is meant to be stored
+// as JSON alongside the generated audio.
+//
+// The text itself is not recorded, only its SHA-256 hash, so it must be kept separately. PreviousText,
+// NextText, PreviousRequestIds and NextRequestIds are not recorded either and should not be relied upon when
+// exact regeneration matters.
+type TextToSpeechRecord struct {
+	VoiceID string `json:"voice_id"`
+	ModelID string `json:"model_id"`
+	// VoiceSettings are the settings used for the request, which are the voice's settings at the time of the
+	// request when the request did not specify any.
+	VoiceSettings VoiceSettings `json:"voice_settings"`
+	// Seed is the seed of the request, which is chosen at random when the request did not specify one.
+	Seed                   uint32      `json:"seed"`
+	OutputFormat           AudioFormat `json:"output_format"`
+	LatencyOptimizations   int         `json:"optimize_streaming_latency"`
+	LanguageCode           string      `json:"language_code,omitempty"`
+	ApplyTextNormalization string      `json:"apply_text_normalization,omitempty"`
+	UsePVCAsIVC            bool        `json:"use_pvc_as_ivc,omitempty"`
+	// TextSHA256 is the hex encoded SHA-256 hash of the text of the request.
+	TextSHA256 string `json:"text_sha256"`
+	// RequestID is the ID the API assigned to the request, as returned in the request-id response header.
+	RequestID string    `json:"request_id,omitempty"`
+	Time      time.Time `json:"time"`
+}
+
+// textHash returns the hex encoded SHA-256 hash of a text.
+func textHash(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+// randomSeed returns a random seed for requests that do not specify one.
+func randomSeed() (uint32, error) {
+	var b [4]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint32(b[:]), nil
+}
+
+// newTextToSpeechRecord completes a request so that it is reproducible, filling in its model, settings and
+// seed, and returns the request along with its record.
+func (c *Client) newTextToSpeechRecord(voiceID string, ttsReq TextToSpeechRequest, queries []QueryFunc) (TextToSpeechRequest, TextToSpeechRecord, error) {
+	if ttsReq.ModelID == "" {
+		ttsReq.ModelID = DefaultModelID
+	}
+	if ttsReq.VoiceSettings == nil {
+		settings, err := c.GetVoiceSettings(voiceID)
+		if err != nil {
+			return ttsReq, TextToSpeechRecord{}, err
+		}
+		ttsReq.VoiceSettings = &settings
+	}
+	if ttsReq.Seed == nil {
+		seed, err := randomSeed()
+		if err != nil {
+			return ttsReq, TextToSpeechRecord{}, err
+		}
+		ttsReq.Seed = &seed
+	}
+
+	q := url.Values{}
+	for _, qf := range queries {
+		qf(&q)
+	}
+	format := AudioFormat(q.Get("output_format"))
+	if format == "" {
+		format = FormatMP3_44100_128
+	}
+	var latency int
+	if v := q.Get("optimize_streaming_latency"); v != "" {
+		var err error
+		if latency, err = strconv.Atoi(v); err != nil {
+			return ttsReq, TextToSpeechRecord{}, fmt.Errorf("invalid latency optimizations %q: %w", v, err)
+		}
+	}
+
+	return ttsReq, TextToSpeechRecord{
+		VoiceID:                voiceID,
+		ModelID:                ttsReq.ModelID,
+		VoiceSettings:          *ttsReq.VoiceSettings,
+		Seed:                   *ttsReq.Seed,
+		OutputFormat:           format,
+		LatencyOptimizations:   latency,
+		LanguageCode:           ttsReq.LanguageCode,
+		ApplyTextNormalization: ttsReq.ApplyTextNormalization,
+		UsePVCAsIVC:            ttsReq.UsePVCAsIVC,
+		TextSHA256:             textHash(ttsReq.Text),
+	}, nil
+}
+
+// RecordedTextToSpeechStream is like TextToSpeechStream but also returns a TextToSpeechRecord that can be passed
+// to Replay to regenerate the same audio. A request without VoiceSettings uses the voice's current settings,
+// which requires an additional request, and a request without a Seed uses a random seed.
+//
+// It takes an io.Writer argument to which the streamed audio will be copied, a string argument that represents
+// the ID of the voice, a TextToSpeechRequest argument and an optional list of QueryFunc 'queries'. The
+// QueryFunc functions relevant for this method are LatencyOptimizations and OutputFormat.
+//
+// It returns the TextToSpeechRecord of the request, or an error.
+func (c *Client) RecordedTextToSpeechStream(streamWriter io.Writer, voiceID string, ttsReq TextToSpeechRequest, queries ...QueryFunc) (TextToSpeechRecord, error) {
+	return c.recordedTextToSpeech(streamWriter, fmt.Sprintf("%s/text-to-speech/%s/stream", c.baseURL, voiceID), voiceID, ttsReq, queries)
+}
+
+func (c *Client) recordedTextToSpeech(w io.Writer, endpoint, voiceID string, ttsReq TextToSpeechRequest, queries []QueryFunc) (TextToSpeechRecord, error) {
+	if err := c.checkOutputFormat(queries); err != nil {
+		return TextToSpeechRecord{}, err
+	}
+	ttsReq, record, err := c.newTextToSpeechRecord(voiceID, ttsReq, queries)
+	if err != nil {
+		return TextToSpeechRecord{}, err
+	}
+	reqBody, err := json.Marshal(ttsReq)
+	if err != nil {
+		return TextToSpeechRecord{}, err
+	}
+	record.Time = time.Now().UTC()
+	header, err := c.doRequestWithHeader(c.ctx, w, http.MethodPost, endpoint, bytes.NewBuffer(reqBody), contentTypeJSON, queries...)
+	if err != nil {
+		return TextToSpeechRecord{}, err
+	}
+	record.RequestID = header.Get("request-id")
+	return record, nil
+}
+
+// RecordedTextToSpeech is like TextToSpeech but also returns a TextToSpeechRecord that can be passed to Replay
+// to regenerate the same audio (see RecordedTextToSpeechStream).
+//
+// It takes a string argument that represents the ID of the voice, a TextToSpeechRequest argument and an
+// optional list of QueryFunc 'queries'. The QueryFunc functions relevant for this method are
+// LatencyOptimizations and OutputFormat.
+//
+// It returns a byte slice that contains the generated audio and the TextToSpeechRecord of the request, or an
+// error.
+func (c *Client) RecordedTextToSpeech(voiceID string, ttsReq TextToSpeechRequest, queries ...QueryFunc) ([]byte, TextToSpeechRecord, error) {
+	b := bytes.Buffer{}
+	record, err := c.recordedTextToSpeech(&b, fmt.Sprintf("%s/text-to-speech/%s", c.baseURL, voiceID), voiceID, ttsReq, queries)
+	if err != nil {
+		return nil, TextToSpeechRecord{}, err
+	}
+	return b.Bytes(), record, nil
+}
+
+// Request returns the TextToSpeechRequest and the queries described by the record, for a given text.
+//
+// It returns an error wrapping ErrTextMismatch if the hash of the text differs from the recorded one.
+func (r TextToSpeechRecord) Request(text string) (TextToSpeechRequest, []QueryFunc, error) {
+	if textHash(text) != r.TextSHA256 {
+		return TextToSpeechRequest{}, nil, fmt.Errorf("%w for request %s", ErrTextMismatch, r.RequestID)
+	}
+	settings := r.VoiceSettings
+	ttsReq := TextToSpeechRequest{
+		Text:                   text,
+		ModelID:                r.ModelID,
+		VoiceSettings:          &settings,
+		Seed:                   Uint32(r.Seed),
+		LanguageCode:           r.LanguageCode,
+		ApplyTextNormalization: r.ApplyTextNormalization,
+		UsePVCAsIVC:            r.UsePVCAsIVC,
+	}
+	queries := []QueryFunc{OutputFormat(r.OutputFormat)}
+	if r.LatencyOptimizations != 0 {
+		queries = append(queries, LatencyOptimizations(r.LatencyOptimizations))
+	}
+	return ttsReq, queries, nil
+}
+
+// ReplayStream regenerates the audio of a recorded request and streams it to a writer (see Replay).
+//
+// It takes an io.Writer argument to which the streamed audio will be copied, a TextToSpeechRecord argument
+// and a string argument that represents the text of the recorded request.
+//
+// It returns nil if successful, an error wrapping ErrTextMismatch if the text is not the recorded one, or an
+// error.
+func (c *Client) ReplayStream(streamWriter io.Writer, record TextToSpeechRecord, text string) error {
+	ttsReq, queries, err := record.Request(text)
+	if err != nil {
+		return err
+	}
+	return c.TextToSpeechStream(streamWriter, record.VoiceID, ttsReq, queries...)
+}
+
+// Replay regenerates the audio of a request recorded by RecordedTextToSpeech or RecordedTextToSpeechStream,
+// using the same voice, model, settings, seed and output options. As generation with a seed is deterministic
+// on a best effort basis only, the audio may still differ slightly, notably after model updates.
+//
+// It takes a TextToSpeechRecord argument and a string argument that represents the text of the recorded
+// request, which is checked against the recorded hash.
+//
+// It returns a byte slice that contains the generated audio, an error wrapping ErrTextMismatch if the text is
+// not the recorded one, or an error.
+func (c *Client) Replay(record TextToSpeechRecord, text string) ([]byte, error) {
+	ttsReq, queries, err := record.Request(text)
+	if err != nil {
+		return nil, err
+	}
+	return c.TextToSpeech(record.VoiceID, ttsReq, queries...)
+}
@@ -0,0 +1,124 @@
+package elevenlabs_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/haguro/elevenlabs-go"
+)
+
+// ttsCapture holds the bodies and URLs of the text to speech requests served by ttsRoutes.
+type ttsCapture struct {
+	bodies []elevenlabs.TextToSpeechRequest
+	urls   []string
+}
+
+// ttsRoutes serves the settings of voice "v1" and text to speech requests, recording them in capture.
+func ttsRoutes(capture *ttsCapture) map[string]http.HandlerFunc {
+	tts := func(w http.ResponseWriter, r *http.Request) {
+		var req elevenlabs.TextToSpeechRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		capture.bodies = append(capture.bodies, req)
+		capture.urls = append(capture.urls, r.URL.Path+"?"+r.URL.RawQuery)
+		w.Header().Set("request-id", fmt.Sprintf("req%d", len(capture.bodies)))
+		fmt.Fprintf(w, "audio-%d", *req.Seed)
+	}
+	return map[string]http.HandlerFunc{
+		"GET /voices/v1/settings": func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"similarity_boost":0.75,"stability":0.5,"style":0,"use_speaker_boost":true}`)
+		},
+		"POST /text-to-speech/v1":        tts,
+		"POST /text-to-speech/v1/stream": tts,
+	}
+}
+
+func TestRecordedTextToSpeechAndReplay(t *testing.T) {
+	capture := &ttsCapture{}
+	server := testServer(t, testServerConfig{routes: ttsRoutes(capture)})
+	defer server.Close()
+	client := elevenlabs.NewMockClient(context.Background(), server.URL, mockAPIKey, mockTimeout)
+
+	ttsReq := elevenlabs.TextToSpeechRequest{Text: "Take one.", LanguageCode: "en"}
+	audio, record, err := client.RecordedTextToSpeech("v1", ttsReq, elevenlabs.OutputFormat(elevenlabs.FormatPCM_16000), elevenlabs.LatencyOptimizations(2))
+	if err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+	if string(audio) != fmt.Sprintf("audio-%d", record.Seed) {
+		t.Errorf("Expected the audio generated with seed %d, got %q", record.Seed, audio)
+	}
+	expSettings := elevenlabs.VoiceSettings{SimilarityBoost: 0.75, Stability: 0.5, Style: elevenlabs.Float32(0), SpeakerBoost: elevenlabs.Bool(true)}
+	if record.VoiceID != "v1" || record.ModelID != elevenlabs.DefaultModelID || record.RequestID != "req1" ||
+		record.OutputFormat != elevenlabs.FormatPCM_16000 || record.LatencyOptimizations != 2 || record.LanguageCode != "en" ||
+		!reflect.DeepEqual(record.VoiceSettings, expSettings) || record.Time.IsZero() || record.TextSHA256 == "" {
+		t.Errorf("Unexpected record %+v", record)
+	}
+
+	// The record survives a round trip through JSON.
+	b, err := json.Marshal(record)
+	if err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+	var decoded elevenlabs.TextToSpeechRecord
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+
+	replayed, err := client.Replay(decoded, "Take one.")
+	if err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+	if string(replayed) != string(audio) {
+		t.Errorf("Expected replayed audio %q, got %q", audio, replayed)
+	}
+	if !reflect.DeepEqual(capture.bodies[0], capture.bodies[1]) {
+		t.Errorf("Expected the replayed request to be the same as the recorded one:\n%+v\n%+v", capture.bodies[0], capture.bodies[1])
+	}
+	if capture.urls[0] != capture.urls[1] {
+		t.Errorf("Expected the replayed request to be sent to the same URL, got %s and %s", capture.urls[0], capture.urls[1])
+	}
+
+	if _, err := client.Replay(decoded, "Take two."); !errors.Is(err, elevenlabs.ErrTextMismatch) {
+		t.Errorf("Expected error %v, got %v", elevenlabs.ErrTextMismatch, err)
+	}
+	if len(capture.bodies) != 2 {
+		t.Errorf("Expected no request for a mismatched text, got %d requests", len(capture.bodies))
+	}
+}
+
+func TestRecordedTextToSpeechStream(t *testing.T) {
+	capture := &ttsCapture{}
+	server := testServer(t, testServerConfig{routes: ttsRoutes(capture)})
+	defer server.Close()
+	client := elevenlabs.NewMockClient(context.Background(), server.URL, mockAPIKey, mockTimeout)
+
+	settings := elevenlabs.VoiceSettings{Stability: 0.3}
+	ttsReq := elevenlabs.TextToSpeechRequest{Text: "Hello", ModelID: "model1", VoiceSettings: &settings, Seed: elevenlabs.Uint32(42)}
+	pr, pw := io.Pipe()
+	go func() {
+		record, err := client.RecordedTextToSpeechStream(pw, "v1", ttsReq)
+		if err == nil && (record.Seed != 42 || record.OutputFormat != elevenlabs.FormatMP3_44100_128 || record.ModelID != "model1" ||
+			!reflect.DeepEqual(record.VoiceSettings, settings)) {
+			err = fmt.Errorf("unexpected record %+v", record)
+		}
+		pw.CloseWithError(err)
+	}()
+	audio, err := io.ReadAll(pr)
+	if err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+	if string(audio) != "audio-42" {
+		t.Errorf("Expected audio %q, got %q", "audio-42", audio)
+	}
+	if len(capture.urls) != 1 || capture.urls[0] != "/text-to-speech/v1/stream?" {
+		t.Errorf("Expected a single streaming request, got %v", capture.urls)
+	}
+}
@@ -0,0 +1,355 @@
+package twilio
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"sync"
+
+	"github.com/haguro/elevenlabs-go"
+)
+
+// DefaultChunkSize is the default size in bytes of the audio sent with each media message, i.e. 20ms of
+// 8kHz μ-law audio.
+const DefaultChunkSize = 160
+
+var (
+	// ErrInterrupted is returned when speech is interrupted by a call to Interrupt (i.e. barge-in).
+	ErrInterrupted = errors.New("speech interrupted")
+	// ErrStreamStopped is returned when the media stream was stopped or its connection was closed.
+	ErrStreamStopped = errors.New("media stream stopped")
+)
+
+// Conn represents a WebSocket connection to Twilio's media stream. It is satisfied by *websocket.Conn
+// from github.com/gorilla/websocket.
+//
+// Adapters for other WebSocket implementations must meet the following contract:
+//   - WriteJSON encodes v as JSON and sends it as a single text message, i.e. one JSON object per message.
+//   - ReadJSON reads the next text message and decodes it into v as JSON. It returns an error, such as io.EOF,
+//     once the connection is closed.
+//   - ReadJSON is only called by Run. WriteJSON calls are serialized by the bridge but may be concurrent with
+//     a ReadJSON call, so reading and writing must not share unsynchronized state.
+type Conn interface {
+	ReadJSON(v interface{}) error
+	WriteJSON(v interface{}) error
+}
+
+// Synthesizer represents the text to speech streaming functionality of the Elevenlabs API. It is satisfied
+// by *elevenlabs.Client.
+type Synthesizer interface {
+	TextToSpeechStream(streamWriter io.Writer, voiceID string, ttsReq elevenlabs.TextToSpeechRequest, queries ...elevenlabs.QueryFunc) error
+}
+
+type mark struct {
+	done      chan struct{}
+	interrupt chan struct{}
+}
+
+// Bridge streams synthesized speech to a Twilio media stream.
+//
+// Run must be called (typically in its own goroutine) to process the events sent by Twilio. Speech can then be
+// sent with Say or SayAll and interrupted with Interrupt.
+//
+// The NewBridge function should be used when instantiating a new Bridge.
+type Bridge struct {
+	// Request is used as a template for the text to speech requests. Its Text field is replaced with the text
+	// passed to Say.
+	Request elevenlabs.TextToSpeechRequest
+	// Queries are passed to TextToSpeechStream, e.g. LatencyOptimizations. The output format is always
+	// elevenlabs.FormatULaw_8000, as required by Twilio, and overrides any OutputFormat in Queries.
+	Queries []elevenlabs.QueryFunc
+	// ChunkSize is the size in bytes of the audio sent with each media message. Defaults to DefaultChunkSize.
+	ChunkSize int
+	// BargeInOnDTMF interrupts any speech when the caller presses a key.
+	BargeInOnDTMF bool
+	// OnEvent, if set, is called by Run for every event received from Twilio.
+	OnEvent func(Event)
+
+	client  Synthesizer
+	conn    Conn
+	voiceID string
+
+	writeMu   sync.Mutex
+	mu        sync.Mutex
+	streamSid string
+	started   chan struct{}
+	stopped   chan struct{}
+	stopOnce  sync.Once
+	interrupt chan struct{}
+	markSeq   int
+	marks     map[string]*mark
+}
+
+// NewBridge creates and returns a new Bridge.
+//
+// It takes a Synthesizer argument (typically an *elevenlabs.Client) used to generate the speech, a Conn
+// argument that represents the WebSocket connection of the media stream and a string argument that
+// represents the ID of the voice to be used.
+func NewBridge(client Synthesizer, conn Conn, voiceID string) *Bridge {
+	return &Bridge{
+		client:    client,
+		conn:      conn,
+		voiceID:   voiceID,
+		started:   make(chan struct{}),
+		stopped:   make(chan struct{}),
+		interrupt: make(chan struct{}),
+		marks:     make(map[string]*mark),
+	}
+}
+
+// StreamSid returns the SID of the media stream, or an empty string if the stream has not started yet.
+func (b *Bridge) StreamSid() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.streamSid
+}
+
+// Run reads and processes the events sent by Twilio until the stream is stopped, the connection fails or the
+// context is done. Since reading from the connection cannot be interrupted, the connection should be closed
+// to make Run return promptly after cancelling the context.
+//
+// It returns nil when the stream is stopped by Twilio, or an error otherwise.
+func (b *Bridge) Run(ctx context.Context) error {
+	defer b.stop()
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var ev Event
+		if err := b.conn.ReadJSON(&ev); err != nil {
+			return err
+		}
+		switch ev.Event {
+		case EventStart:
+			b.mu.Lock()
+			if b.streamSid == "" {
+				b.streamSid = ev.StreamSid
+				if b.streamSid == "" && ev.Start != nil {
+					b.streamSid = ev.Start.StreamSid
+				}
+				close(b.started)
+			}
+			b.mu.Unlock()
+		case EventMark:
+			if ev.Mark != nil {
+				b.mu.Lock()
+				if m, ok := b.marks[ev.Mark.Name]; ok {
+					close(m.done)
+					delete(b.marks, ev.Mark.Name)
+				}
+				b.mu.Unlock()
+			}
+		case EventDTMF:
+			if b.BargeInOnDTMF {
+				if err := b.Interrupt(); err != nil {
+					return err
+				}
+			}
+		}
+		if b.OnEvent != nil {
+			b.OnEvent(ev)
+		}
+		if ev.Event == EventStop {
+			return nil
+		}
+	}
+}
+
+func (b *Bridge) stop() {
+	b.stopOnce.Do(func() { close(b.stopped) })
+}
+
+func (b *Bridge) send(msg OutboundMessage) error {
+	b.writeMu.Lock()
+	defer b.writeMu.Unlock()
+	return b.conn.WriteJSON(msg)
+}
+
+func (b *Bridge) waitStarted(ctx context.Context) error {
+	select {
+	case <-b.stopped:
+		return ErrStreamStopped
+	default:
+	}
+	select {
+	case <-b.started:
+		return nil
+	case <-b.stopped:
+		return ErrStreamStopped
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Say synthesizes a given text and streams the audio to Twilio as media messages followed by a mark message.
+// It waits for the stream to start if it has not already.
+//
+// It returns the name of the mark, which can be passed to WaitMark to wait until the audio has been played
+// to the caller, or an error. ErrInterrupted is returned if Interrupt was called while the audio was being sent.
+func (b *Bridge) Say(ctx context.Context, text string) (string, error) {
+	if err := b.waitStarted(ctx); err != nil {
+		return "", err
+	}
+	b.mu.Lock()
+	interrupt := b.interrupt
+	b.mu.Unlock()
+
+	w := &mediaWriter{bridge: b, ctx: ctx, interrupt: interrupt, streamSid: b.StreamSid()}
+	req := b.Request
+	req.Text = text
+	queries := append(append([]elevenlabs.QueryFunc(nil), b.Queries...), func(q *url.Values) {
+		// Replaces any output format set by the bridge's queries.
+		q.Set("output_format", string(elevenlabs.FormatULaw_8000))
+	})
+	err := b.client.TextToSpeechStream(w, b.voiceID, req, queries...)
+	if w.err != nil {
+		// The writer aborted the stream, which is reported as a less specific error by the client.
+		return "", w.err
+	}
+	if err != nil {
+		return "", err
+	}
+	if err := w.flush(); err != nil {
+		return "", err
+	}
+
+	b.mu.Lock()
+	b.markSeq++
+	name := fmt.Sprintf("utterance-%d", b.markSeq)
+	b.marks[name] = &mark{done: make(chan struct{}), interrupt: interrupt}
+	b.mu.Unlock()
+	if err := b.send(OutboundMessage{Event: EventMark, StreamSid: w.streamSid, Mark: &MarkPayload{Name: name}}); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// SayAll calls Say for every text received from a channel until the channel is closed.
+//
+// It returns nil once all texts have been sent, or the first error returned by Say (including ErrInterrupted).
+func (b *Bridge) SayAll(ctx context.Context, texts <-chan string) error {
+	for {
+		select {
+		case text, ok := <-texts:
+			if !ok {
+				return nil
+			}
+			if _, err := b.Say(ctx, text); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// WaitMark waits until Twilio reports that the audio sent before a given mark has been played.
+//
+// It returns nil once the audio has been played, ErrInterrupted if the audio was cleared by Interrupt, or an
+// error if the stream stopped or the context is done.
+func (b *Bridge) WaitMark(ctx context.Context, name string) error {
+	b.mu.Lock()
+	m, ok := b.marks[name]
+	b.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	select {
+	case <-m.done:
+		select {
+		case <-m.interrupt:
+			return ErrInterrupted
+		default:
+			return nil
+		}
+	case <-m.interrupt:
+		return ErrInterrupted
+	case <-b.stopped:
+		return ErrStreamStopped
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Interrupt stops any speech being sent and sends a clear message to Twilio so that buffered audio is
+// discarded. It is meant to be called when the caller starts talking (barge-in).
+//
+// It returns nil if successful or an error otherwise.
+func (b *Bridge) Interrupt() error {
+	// Holding writeMu guarantees that no media message of the interrupted speech is sent after the clear message.
+	b.writeMu.Lock()
+	defer b.writeMu.Unlock()
+	b.mu.Lock()
+	close(b.interrupt)
+	b.interrupt = make(chan struct{})
+	sid := b.streamSid
+	b.mu.Unlock()
+	if sid == "" {
+		return nil
+	}
+	return b.conn.WriteJSON(OutboundMessage{Event: EventClear, StreamSid: sid})
+}
+
+// mediaWriter frames the audio written to it as media messages of the bridge's chunk size.
+type mediaWriter struct {
+	bridge    *Bridge
+	ctx       context.Context
+	interrupt chan struct{}
+	streamSid string
+	buf       []byte
+	err       error
+}
+
+func (w *mediaWriter) Write(p []byte) (int, error) {
+	size := w.bridge.ChunkSize
+	if size <= 0 {
+		size = DefaultChunkSize
+	}
+	w.buf = append(w.buf, p...)
+	for len(w.buf) >= size {
+		if err := w.sendChunk(w.buf[:size]); err != nil {
+			return 0, err
+		}
+		w.buf = w.buf[size:]
+	}
+	return len(p), nil
+}
+
+func (w *mediaWriter) flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	err := w.sendChunk(w.buf)
+	w.buf = nil
+	return err
+}
+
+func (w *mediaWriter) sendChunk(chunk []byte) error {
+	w.bridge.writeMu.Lock()
+	defer w.bridge.writeMu.Unlock()
+	select {
+	case <-w.interrupt:
+		w.err = ErrInterrupted
+	case <-w.bridge.stopped:
+		w.err = ErrStreamStopped
+	case <-w.ctx.Done():
+		w.err = w.ctx.Err()
+	default:
+	}
+	if w.err != nil {
+		return w.err
+	}
+	msg := OutboundMessage{
+		Event:     EventMedia,
+		StreamSid: w.streamSid,
+		Media:     &MediaPayload{Payload: base64.StdEncoding.EncodeToString(chunk)},
+	}
+	if err := w.bridge.conn.WriteJSON(msg); err != nil {
+		w.err = err
+		return err
+	}
+	return nil
+}
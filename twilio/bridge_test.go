@@ -0,0 +1,266 @@
+package twilio_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/haguro/elevenlabs-go"
+	"github.com/haguro/elevenlabs-go/twilio"
+)
+
+const testStreamSid = "MZ00000000000000000000000000000000"
+
+// fakeTwilio mimics Twilio's end of a media stream WebSocket. Messages are passed through JSON to mirror what
+// would be sent over the wire.
+type fakeTwilio struct {
+	inbound  chan []byte
+	outbound chan twilio.OutboundMessage
+}
+
+func newFakeTwilio() *fakeTwilio {
+	return &fakeTwilio{inbound: make(chan []byte, 10), outbound: make(chan twilio.OutboundMessage, 100)}
+}
+
+func (f *fakeTwilio) ReadJSON(v interface{}) error {
+	b, ok := <-f.inbound
+	if !ok {
+		return io.EOF
+	}
+	return json.Unmarshal(b, v)
+}
+
+func (f *fakeTwilio) WriteJSON(v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var msg twilio.OutboundMessage
+	if err := json.Unmarshal(b, &msg); err != nil {
+		return err
+	}
+	f.outbound <- msg
+	return nil
+}
+
+func (f *fakeTwilio) sendEvent(t *testing.T, raw string) {
+	t.Helper()
+	f.inbound <- []byte(raw)
+}
+
+func (f *fakeTwilio) start(t *testing.T) {
+	t.Helper()
+	f.sendEvent(t, `{"event":"connected","protocol":"Call","version":"1.0.0"}`)
+	f.sendEvent(t, `{"event":"start","sequenceNumber":"1","streamSid":"`+testStreamSid+`","start":{"streamSid":"`+testStreamSid+`","callSid":"CA0","tracks":["inbound"],"mediaFormat":{"encoding":"audio/x-mulaw","sampleRate":8000,"channels":1}}}`)
+}
+
+func (f *fakeTwilio) next(t *testing.T) twilio.OutboundMessage {
+	t.Helper()
+	select {
+	case msg := <-f.outbound:
+		if msg.StreamSid != testStreamSid {
+			t.Errorf("Expected message with stream SID %q, got %q", testStreamSid, msg.StreamSid)
+		}
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for a message from the bridge")
+	}
+	return twilio.OutboundMessage{}
+}
+
+// recordingSynth records the text to speech request and writes a fixed response.
+type recordingSynth struct {
+	audio   []byte
+	voiceID string
+	req     elevenlabs.TextToSpeechRequest
+	queries []elevenlabs.QueryFunc
+}
+
+func (s *recordingSynth) TextToSpeechStream(w io.Writer, voiceID string, req elevenlabs.TextToSpeechRequest, queries ...elevenlabs.QueryFunc) error {
+	s.voiceID, s.req, s.queries = voiceID, req, queries
+	_, err := w.Write(s.audio)
+	return err
+}
+
+func runBridge(t *testing.T, b *twilio.Bridge) chan error {
+	t.Helper()
+	done := make(chan error, 1)
+	go func() { done <- b.Run(context.Background()) }()
+	return done
+}
+
+func TestBridgeSay(t *testing.T) {
+	audio := bytes.Repeat([]byte{0xFF, 0x7F, 0x00, 0x80}, 100)
+	client := &recordingSynth{audio: audio}
+	fake := newFakeTwilio()
+	bridge := twilio.NewBridge(client, fake, "voiceID")
+	bridge.Request.ModelID = "model1"
+	bridge.Queries = []elevenlabs.QueryFunc{elevenlabs.LatencyOptimizations(3), elevenlabs.OutputFormat(elevenlabs.FormatPCM_16000)}
+	done := runBridge(t, bridge)
+	fake.start(t)
+
+	name, err := bridge.Say(context.Background(), "Hello caller")
+	if err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+	q := url.Values{}
+	for _, qf := range client.queries {
+		qf(&q)
+	}
+	if enc := q.Encode(); enc != "optimize_streaming_latency=3&output_format=ulaw_8000" {
+		t.Errorf("Unexpected query string %q", enc)
+	}
+	if client.req.Text != "Hello caller" || client.req.ModelID != "model1" || client.voiceID != "voiceID" {
+		t.Errorf("Unexpected request %+v for voice %q", client.req, client.voiceID)
+	}
+	if bridge.StreamSid() != testStreamSid {
+		t.Errorf("Expected stream SID %q, got %q", testStreamSid, bridge.StreamSid())
+	}
+
+	var got []byte
+	for _, expLen := range []int{160, 160, 80} {
+		msg := fake.next(t)
+		if msg.Event != twilio.EventMedia || msg.Media == nil {
+			t.Fatalf("Expected a media message, got %+v", msg)
+		}
+		payload, err := base64.StdEncoding.DecodeString(msg.Media.Payload)
+		if err != nil {
+			t.Fatalf("Failed to decode media payload: %s", err)
+		}
+		if len(payload) != expLen {
+			t.Errorf("Expected a payload of %d bytes, got %d", expLen, len(payload))
+		}
+		got = append(got, payload...)
+	}
+	if !bytes.Equal(got, audio) {
+		t.Error("Expected media payloads to contain the synthesized audio")
+	}
+	if msg := fake.next(t); msg.Event != twilio.EventMark || msg.Mark == nil || msg.Mark.Name != name {
+		t.Fatalf("Expected a mark message named %q, got %+v", name, msg)
+	}
+
+	fake.sendEvent(t, `{"event":"mark","streamSid":"`+testStreamSid+`","mark":{"name":"`+name+`"}}`)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := bridge.WaitMark(ctx, name); err != nil {
+		t.Errorf("Expected no errors waiting for mark, got %q", err)
+	}
+
+	fake.sendEvent(t, `{"event":"stop","streamSid":"`+testStreamSid+`","stop":{"callSid":"CA0"}}`)
+	if err := <-done; err != nil {
+		t.Errorf("Expected Run to return no errors when the stream stops, got %q", err)
+	}
+	if _, err := bridge.Say(context.Background(), "Too late"); !errors.Is(err, twilio.ErrStreamStopped) {
+		t.Errorf("Expected error %q, got %v", twilio.ErrStreamStopped, err)
+	}
+}
+
+// endlessSynth streams silence until the writer returns an error.
+type endlessSynth struct {
+	writing chan struct{}
+}
+
+func (s *endlessSynth) TextToSpeechStream(w io.Writer, voiceID string, req elevenlabs.TextToSpeechRequest, queries ...elevenlabs.QueryFunc) error {
+	chunk := bytes.Repeat([]byte{0xFF}, 160)
+	for i := 0; ; i++ {
+		if _, err := w.Write(chunk); err != nil {
+			return errors.New("stream aborted")
+		}
+		if i == 1 {
+			close(s.writing)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestBridgeInterrupt(t *testing.T) {
+	synth := &endlessSynth{writing: make(chan struct{})}
+	fake := newFakeTwilio()
+	bridge := twilio.NewBridge(synth, fake, "voiceID")
+	done := runBridge(t, bridge)
+	fake.start(t)
+
+	sayErr := make(chan error, 1)
+	go func() {
+		_, err := bridge.Say(context.Background(), "A very long answer")
+		sayErr <- err
+	}()
+	<-synth.writing
+	if err := bridge.Interrupt(); err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+	if err := <-sayErr; !errors.Is(err, twilio.ErrInterrupted) {
+		t.Errorf("Expected error %q, got %v", twilio.ErrInterrupted, err)
+	}
+
+	cleared := false
+	for len(fake.outbound) > 0 {
+		msg := fake.next(t)
+		if msg.Event == twilio.EventClear {
+			cleared = true
+		}
+		if cleared && msg.Event == twilio.EventMedia {
+			t.Error("Expected no media messages after the clear message")
+		}
+	}
+	if !cleared {
+		t.Error("Expected a clear message to be sent")
+	}
+
+	close(fake.inbound)
+	if err := <-done; err != io.EOF {
+		t.Errorf("Expected Run to return %v when the connection is closed, got %v", io.EOF, err)
+	}
+}
+
+type fixedSynth struct{}
+
+func (fixedSynth) TextToSpeechStream(w io.Writer, voiceID string, req elevenlabs.TextToSpeechRequest, queries ...elevenlabs.QueryFunc) error {
+	_, err := w.Write([]byte(req.Text))
+	return err
+}
+
+func TestBridgeSayAllAndDTMFBargeIn(t *testing.T) {
+	fake := newFakeTwilio()
+	bridge := twilio.NewBridge(fixedSynth{}, fake, "voiceID")
+	bridge.BargeInOnDTMF = true
+	var events []string
+	bridge.OnEvent = func(ev twilio.Event) { events = append(events, ev.Event) }
+	done := runBridge(t, bridge)
+	fake.start(t)
+
+	texts := make(chan string, 2)
+	texts <- "one"
+	texts <- "two"
+	close(texts)
+	if err := bridge.SayAll(context.Background(), texts); err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+	for _, exp := range []string{"one", "two"} {
+		msg := fake.next(t)
+		if payload, _ := base64.StdEncoding.DecodeString(msg.Media.Payload); string(payload) != exp {
+			t.Errorf("Expected payload %q, got %q", exp, payload)
+		}
+		if msg := fake.next(t); msg.Event != twilio.EventMark {
+			t.Errorf("Expected a mark message, got %+v", msg)
+		}
+	}
+
+	fake.sendEvent(t, `{"event":"dtmf","streamSid":"`+testStreamSid+`","dtmf":{"track":"inbound_track","digit":"1"}}`)
+	if msg := fake.next(t); msg.Event != twilio.EventClear {
+		t.Errorf("Expected a clear message after a DTMF event, got %+v", msg)
+	}
+	fake.sendEvent(t, `{"event":"stop","streamSid":"`+testStreamSid+`"}`)
+	if err := <-done; err != nil {
+		t.Errorf("Expected no errors, got %q", err)
+	}
+	if exp := []string{"connected", "start", "dtmf", "stop"}; len(events) != len(exp) {
+		t.Errorf("Expected OnEvent to be called for events %v, got %v", exp, events)
+	}
+}
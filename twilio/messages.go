@@ -0,0 +1,85 @@
+// Package twilio bridges the Elevenlabs text to speech streaming API and Twilio Media Streams, allowing
+// synthesized speech to be played to a phone call connected to a bidirectional media stream.
+//
+// The package does not depend on a particular WebSocket implementation. Any connection that can read and
+// write JSON messages, such as a *websocket.Conn from github.com/gorilla/websocket, satisfies Conn.
+package twilio
+
+// Inbound event names sent by Twilio over a media stream.
+const (
+	EventConnected = "connected"
+	EventStart     = "start"
+	EventMedia     = "media"
+	EventMark      = "mark"
+	EventDTMF      = "dtmf"
+	EventStop      = "stop"
+)
+
+// EventClear is the outbound event name sent to Twilio to clear the buffered audio of a media stream. Media and
+// mark messages are sent to Twilio as well, using EventMedia and EventMark.
+const EventClear = "clear"
+
+// Event represents a message received from Twilio over a media stream.
+type Event struct {
+	Event          string        `json:"event"`
+	SequenceNumber string        `json:"sequenceNumber,omitempty"`
+	StreamSid      string        `json:"streamSid,omitempty"`
+	Protocol       string        `json:"protocol,omitempty"`
+	Version        string        `json:"version,omitempty"`
+	Start          *StartPayload `json:"start,omitempty"`
+	Media          *MediaPayload `json:"media,omitempty"`
+	Mark           *MarkPayload  `json:"mark,omitempty"`
+	DTMF           *DTMFPayload  `json:"dtmf,omitempty"`
+	Stop           *StopPayload  `json:"stop,omitempty"`
+}
+
+// StartPayload contains the metadata of a media stream sent with the "start" event.
+type StartPayload struct {
+	StreamSid        string            `json:"streamSid"`
+	AccountSid       string            `json:"accountSid"`
+	CallSid          string            `json:"callSid"`
+	Tracks           []string          `json:"tracks"`
+	CustomParameters map[string]string `json:"customParameters,omitempty"`
+	MediaFormat      MediaFormat       `json:"mediaFormat"`
+}
+
+// MediaFormat describes the encoding of the audio carried by a media stream.
+type MediaFormat struct {
+	Encoding   string `json:"encoding"`
+	SampleRate int    `json:"sampleRate"`
+	Channels   int    `json:"channels"`
+}
+
+// MediaPayload carries base64 encoded μ-law audio.
+type MediaPayload struct {
+	Track     string `json:"track,omitempty"`
+	Chunk     string `json:"chunk,omitempty"`
+	Timestamp string `json:"timestamp,omitempty"`
+	Payload   string `json:"payload"`
+}
+
+// MarkPayload carries the name of a mark.
+type MarkPayload struct {
+	Name string `json:"name"`
+}
+
+// DTMFPayload carries a digit pressed by the caller.
+type DTMFPayload struct {
+	Track string `json:"track"`
+	Digit string `json:"digit"`
+}
+
+// StopPayload contains the metadata sent with the "stop" event.
+type StopPayload struct {
+	AccountSid string `json:"accountSid"`
+	CallSid    string `json:"callSid"`
+}
+
+// OutboundMessage represents a message sent to Twilio over a media stream. Its Event is one of
+// EventMedia, EventMark or EventClear.
+type OutboundMessage struct {
+	Event     string        `json:"event"`
+	StreamSid string        `json:"streamSid"`
+	Media     *MediaPayload `json:"media,omitempty"`
+	Mark      *MarkPayload  `json:"mark,omitempty"`
+}
@@ -0,0 +1,272 @@
+package twilio_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/haguro/elevenlabs-go/twilio"
+)
+
+const (
+	wsGUID       = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+	opText       = 0x1
+	opClose      = 0x8
+	maxFrameSize = 1 << 20
+)
+
+// wsConn is a minimal WebSocket connection (RFC 6455) that satisfies twilio.Conn. It supports unfragmented text
+// and close frames only, which is all that Twilio media streams use.
+type wsConn struct {
+	conn   net.Conn
+	r      *bufio.Reader
+	client bool
+}
+
+func wsAccept(key string) string {
+	h := sha1.Sum([]byte(key + wsGUID))
+	return base64.StdEncoding.EncodeToString(h[:])
+}
+
+// wsUpgrade performs the server side of the WebSocket handshake.
+func wsUpgrade(w http.ResponseWriter, r *http.Request) (*wsConn, error) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") || key == "" {
+		return nil, errors.New("not a WebSocket handshake")
+	}
+	conn, rw, err := w.(http.Hijacker).Hijack()
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n", wsAccept(key))
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &wsConn{conn: conn, r: rw.Reader}, nil
+}
+
+// wsDial performs the client side of the WebSocket handshake.
+func wsDial(addr, path string) (*wsConn, error) {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, 16)
+	rand.Read(nonce)
+	key := base64.StdEncoding.EncodeToString(nonce)
+	fmt.Fprintf(conn, "GET %s HTTP/1.1\r\nHost: %s\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Key: %s\r\nSec-WebSocket-Version: 13\r\n\r\n", path, addr, key)
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, nil)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols || resp.Header.Get("Sec-WebSocket-Accept") != wsAccept(key) {
+		conn.Close()
+		return nil, fmt.Errorf("handshake failed with status %d", resp.StatusCode)
+	}
+	return &wsConn{conn: conn, r: r, client: true}, nil
+}
+
+// writeFrame writes a final frame. Frames sent by clients are masked, as required by the RFC.
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+	header := []byte{0x80 | opcode, 0}
+	switch n := len(payload); {
+	case n < 126:
+		header[1] = byte(n)
+	case n <= 0xFFFF:
+		header[1] = 126
+		header = append(header, 0, 0)
+		binary.BigEndian.PutUint16(header[2:], uint16(n))
+	default:
+		header[1] = 127
+		header = append(header, make([]byte, 8)...)
+		binary.BigEndian.PutUint64(header[2:], uint64(n))
+	}
+	if c.client {
+		header[1] |= 0x80
+		key := make([]byte, 4)
+		rand.Read(key)
+		header = append(header, key...)
+		masked := make([]byte, len(payload))
+		for i, b := range payload {
+			masked[i] = b ^ key[i%4]
+		}
+		payload = masked
+	}
+	_, err := c.conn.Write(append(header, payload...))
+	return err
+}
+
+// readFrame reads a frame and checks that it is final and masked only if sent by a client.
+func (c *wsConn) readFrame() (byte, []byte, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(c.r, header); err != nil {
+		return 0, nil, err
+	}
+	if header[0]&0x80 == 0 {
+		return 0, nil, errors.New("fragmented frame")
+	}
+	if masked := header[1]&0x80 != 0; masked == c.client {
+		return 0, nil, fmt.Errorf("unexpected frame masking %t", masked)
+	}
+	n := uint64(header[1] & 0x7F)
+	switch n {
+	case 126:
+		b := make([]byte, 2)
+		if _, err := io.ReadFull(c.r, b); err != nil {
+			return 0, nil, err
+		}
+		n = uint64(binary.BigEndian.Uint16(b))
+	case 127:
+		b := make([]byte, 8)
+		if _, err := io.ReadFull(c.r, b); err != nil {
+			return 0, nil, err
+		}
+		n = binary.BigEndian.Uint64(b)
+	}
+	if n > maxFrameSize {
+		return 0, nil, fmt.Errorf("frame of %d bytes too large", n)
+	}
+	var key []byte
+	if !c.client {
+		key = make([]byte, 4)
+		if _, err := io.ReadFull(c.r, key); err != nil {
+			return 0, nil, err
+		}
+	}
+	payload := make([]byte, n)
+	if _, err := io.ReadFull(c.r, payload); err != nil {
+		return 0, nil, err
+	}
+	for i := range key {
+		for j := i; j < len(payload); j += 4 {
+			payload[j] ^= key[i]
+		}
+	}
+	return header[0] & 0x0F, payload, nil
+}
+
+func (c *wsConn) WriteJSON(v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.writeFrame(opText, b)
+}
+
+func (c *wsConn) ReadJSON(v interface{}) error {
+	opcode, payload, err := c.readFrame()
+	if err != nil {
+		return err
+	}
+	switch opcode {
+	case opText:
+		return json.Unmarshal(payload, v)
+	case opClose:
+		return io.EOF
+	}
+	return fmt.Errorf("unexpected opcode %d", opcode)
+}
+
+// readMessage reads a text frame and checks that it holds a single JSON object.
+func (c *wsConn) readMessage(t *testing.T) map[string]json.RawMessage {
+	t.Helper()
+	c.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	opcode, payload, err := c.readFrame()
+	if err != nil {
+		t.Fatalf("Failed to read frame: %s", err)
+	}
+	if opcode != opText {
+		t.Fatalf("Expected a text frame, got opcode %d", opcode)
+	}
+	var msg map[string]json.RawMessage
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	if err := dec.Decode(&msg); err != nil || dec.More() {
+		t.Fatalf("Expected a frame holding a single JSON object, got %q", payload)
+	}
+	return msg
+}
+
+func TestBridgeOverWebSocket(t *testing.T) {
+	audio := bytes.Repeat([]byte{0xFF}, 200)
+	runErr := make(chan error, 1)
+	sayErr := make(chan error, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := wsUpgrade(w, r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer conn.conn.Close()
+		bridge := twilio.NewBridge(&recordingSynth{audio: audio}, conn, "voiceID")
+		go func() {
+			_, err := bridge.Say(context.Background(), "Hello caller")
+			sayErr <- err
+		}()
+		runErr <- bridge.Run(context.Background())
+	}))
+	defer server.Close()
+
+	twilioConn, err := wsDial(server.Listener.Addr().String(), "/media")
+	if err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+	defer twilioConn.conn.Close()
+	for _, raw := range []string{
+		`{"event":"connected","protocol":"Call","version":"1.0.0"}`,
+		`{"event":"start","sequenceNumber":"1","streamSid":"` + testStreamSid + `","start":{"streamSid":"` + testStreamSid + `","callSid":"CA0","tracks":["inbound"],"mediaFormat":{"encoding":"audio/x-mulaw","sampleRate":8000,"channels":1}}}`,
+	} {
+		if err := twilioConn.writeFrame(opText, []byte(raw)); err != nil {
+			t.Fatalf("Expected no errors, got %q", err)
+		}
+	}
+
+	// Each message carries a payload named after its event.
+	var got []byte
+	for _, event := range []string{"media", "media", "mark"} {
+		msg := twilioConn.readMessage(t)
+		if string(msg["event"]) != `"`+event+`"` || string(msg["streamSid"]) != `"`+testStreamSid+`"` || msg[event] == nil {
+			t.Fatalf("Expected a %s message, got %v", event, msg)
+		}
+		if event == "media" {
+			var media twilio.MediaPayload
+			json.Unmarshal(msg["media"], &media)
+			payload, _ := base64.StdEncoding.DecodeString(media.Payload)
+			got = append(got, payload...)
+		}
+	}
+	if !bytes.Equal(got, audio) {
+		t.Errorf("Expected media messages to carry %d bytes of audio, got %d", len(audio), len(got))
+	}
+	if err := <-sayErr; err != nil {
+		t.Errorf("Expected no errors, got %q", err)
+	}
+
+	if err := twilioConn.writeFrame(opText, []byte(`{"event":"stop","streamSid":"`+testStreamSid+`"}`)); err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+	select {
+	case err := <-runErr:
+		if err != nil {
+			t.Errorf("Expected Run to return no errors when the stream stops, got %q", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for Run to return")
+	}
+}
@@ -0,0 +1,150 @@
+package elevenlabs
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultVoiceCacheTTL is the default time the voices cached by a Client are used before being retrieved again.
+const DefaultVoiceCacheTTL = 5 * time.Minute
+
+// VoiceQuery represents the criteria used by FindVoices to select voices. Zero valued fields are ignored, so
+// the zero value of VoiceQuery matches all voices.
+type VoiceQuery struct {
+	// Name matches voices with the given name, ignoring case.
+	Name string
+	// Labels matches voices that have all the given labels, e.g. {"accent": "british", "gender": "female"}.
+	// Label values are compared ignoring case.
+	Labels map[string]string
+	// Category matches voices of the given category, such as "premade", "cloned" or "generated".
+	Category string
+	// ModelID matches voices whose HighQualityBaseModelIds include the given model.
+	ModelID string
+}
+
+// Match reports whether a voice matches the query.
+func (q VoiceQuery) Match(v Voice) bool {
+	if q.Name != "" && !strings.EqualFold(v.Name, q.Name) {
+		return false
+	}
+	if q.Category != "" && v.Category != q.Category {
+		return false
+	}
+	for k, want := range q.Labels {
+		got, ok := v.Labels[k]
+		if !ok || !strings.EqualFold(got, want) {
+			return false
+		}
+	}
+	if q.ModelID != "" {
+		found := false
+		for _, id := range v.HighQualityBaseModelIds {
+			if id == q.ModelID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// SetVoiceCacheTTL sets the time the voices retrieved by the voice lookup methods are cached for.
+//
+// It takes a time.Duration argument that represents the new TTL. A value of 0 or less disables caching.
+func (c *Client) SetVoiceCacheTTL(ttl time.Duration) {
+	c.voices.setTTL(ttl)
+}
+
+// InvalidateVoiceCache discards the cached voices so that the next lookup retrieves them again. The cache is
+// invalidated automatically by AddVoice, EditVoice and DeleteVoice.
+func (c *Client) InvalidateVoiceCache() {
+	c.voices.invalidate()
+}
+
+// CachedVoices returns the voices of GetVoices, retrieving them only if they are not cached or the cached
+// voices are older than the cache TTL (see SetVoiceCacheTTL).
+//
+// It returns a slice of Voice objects, which must not be modified, or an error.
+func (c *Client) CachedVoices() ([]Voice, error) {
+	return c.voices.get(c.GetVoices)
+}
+
+// FindVoices returns the voices matching a query, using the cached voices (see CachedVoices).
+//
+// It takes a VoiceQuery argument that represents the criteria the voices must match.
+//
+// It returns a slice of matching Voice objects, which is empty if no voice matches, or an error.
+func (c *Client) FindVoices(query VoiceQuery) ([]Voice, error) {
+	voices, err := c.CachedVoices()
+	if err != nil {
+		return nil, err
+	}
+	matches := []Voice{}
+	for _, v := range voices {
+		if query.Match(v) {
+			matches = append(matches, v)
+		}
+	}
+	return matches, nil
+}
+
+// FindVoice returns the voice with a given name, using the cached voices (see CachedVoices). A voice whose name
+// matches exactly is preferred over voices whose name only differs in case.
+//
+// It takes a string argument that represents the name of the voice.
+//
+// It returns the matching Voice, or an error wrapping ErrVoiceNotFound if no voice has that name or
+// ErrAmbiguousVoice if several voices do.
+func (c *Client) FindVoice(name string) (Voice, error) {
+	voices, err := c.CachedVoices()
+	if err != nil {
+		return Voice{}, err
+	}
+	var exact, folded []Voice
+	for _, v := range voices {
+		switch {
+		case v.Name == name:
+			exact = append(exact, v)
+		case strings.EqualFold(v.Name, name):
+			folded = append(folded, v)
+		}
+	}
+	matches := exact
+	if len(matches) == 0 {
+		matches = folded
+	}
+	switch len(matches) {
+	case 0:
+		return Voice{}, fmt.Errorf("%w: %q", ErrVoiceNotFound, name)
+	case 1:
+		return matches[0], nil
+	}
+	return Voice{}, fmt.Errorf("%w: %q matches %d voices", ErrAmbiguousVoice, name, len(matches))
+}
+
+// ResolveVoiceID returns the ID of a voice given either its ID or its name, using the cached voices (see
+// CachedVoices). This allows voices to be referred to by name in configuration.
+//
+// It takes a string argument that represents the ID or the name of the voice.
+//
+// It returns the voice ID, or an error as returned by FindVoice.
+func (c *Client) ResolveVoiceID(nameOrId string) (string, error) {
+	voices, err := c.CachedVoices()
+	if err != nil {
+		return "", err
+	}
+	for _, v := range voices {
+		if v.VoiceId == nameOrId {
+			return v.VoiceId, nil
+		}
+	}
+	v, err := c.FindVoice(nameOrId)
+	if err != nil {
+		return "", err
+	}
+	return v.VoiceId, nil
+}
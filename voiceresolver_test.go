@@ -0,0 +1,139 @@
+package elevenlabs_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/haguro/elevenlabs-go"
+)
+
+const resolverVoices = `{"voices":[
+	{"voice_id":"v1","name":"Rachel","category":"premade","labels":{"accent":"american","gender":"female"},"high_quality_base_model_ids":["eleven_turbo_v2"]},
+	{"voice_id":"v2","name":"George","category":"premade","labels":{"accent":"british","gender":"male"},"high_quality_base_model_ids":["eleven_turbo_v2","eleven_multilingual_v2"]},
+	{"voice_id":"v3","name":"Alice","category":"cloned","labels":{"accent":"British","gender":"female"},"high_quality_base_model_ids":["eleven_multilingual_v2"]},
+	{"voice_id":"v4","name":"alice","category":"generated","labels":{"accent":"british","gender":"female"}},
+	{"voice_id":"v5","name":"Sam","category":"cloned"},
+	{"voice_id":"v6","name":"SAM","category":"cloned"}
+]}`
+
+// resolverRoutes serves a fixed list of voices and the requests that change them.
+var resolverRoutes = map[string]http.HandlerFunc{
+	"GET /voices": func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, resolverVoices)
+	},
+	"DELETE /voices/v1": func(w http.ResponseWriter, r *http.Request) {},
+	"POST /voices/add": func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"voice_id":"v7"}`)
+	},
+}
+
+func voiceIDs(voices []elevenlabs.Voice) []string {
+	ids := []string{}
+	for _, v := range voices {
+		ids = append(ids, v.VoiceId)
+	}
+	return ids
+}
+
+func TestFindVoices(t *testing.T) {
+	server := testServer(t, testServerConfig{routes: resolverRoutes})
+	defer server.Close()
+	client := elevenlabs.NewMockClient(context.Background(), server.URL, mockAPIKey, mockTimeout)
+
+	tests := []struct {
+		name     string
+		query    elevenlabs.VoiceQuery
+		expected []string
+	}{
+		{"All", elevenlabs.VoiceQuery{}, []string{"v1", "v2", "v3", "v4", "v5", "v6"}},
+		{"Name", elevenlabs.VoiceQuery{Name: "ALICE"}, []string{"v3", "v4"}},
+		{"Labels", elevenlabs.VoiceQuery{Labels: map[string]string{"accent": "british", "gender": "female"}}, []string{"v3", "v4"}},
+		{"Category", elevenlabs.VoiceQuery{Category: "cloned"}, []string{"v3", "v5", "v6"}},
+		{"Model", elevenlabs.VoiceQuery{ModelID: "eleven_multilingual_v2"}, []string{"v2", "v3"}},
+		{"Combined", elevenlabs.VoiceQuery{Labels: map[string]string{"accent": "british"}, ModelID: "eleven_turbo_v2"}, []string{"v2"}},
+		{"NoMatch", elevenlabs.VoiceQuery{Labels: map[string]string{"accent": "irish"}}, []string{}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			voices, err := client.FindVoices(tc.query)
+			if err != nil {
+				t.Fatalf("Expected no errors, got %q", err)
+			}
+			if ids := voiceIDs(voices); !reflect.DeepEqual(ids, tc.expected) {
+				t.Errorf("Expected voices %v, got %v", tc.expected, ids)
+			}
+		})
+	}
+}
+
+func TestFindVoice(t *testing.T) {
+	server := testServer(t, testServerConfig{routes: resolverRoutes})
+	defer server.Close()
+	client := elevenlabs.NewMockClient(context.Background(), server.URL, mockAPIKey, mockTimeout)
+
+	tests := []struct {
+		name        string
+		voice       string
+		expectedID  string
+		expectedErr error
+	}{
+		{"Exact", "Rachel", "v1", nil},
+		{"CaseInsensitive", "george", "v2", nil},
+		{"ExactPreferred", "alice", "v4", nil},
+		{"ID", "v3", "v3", nil},
+		{"Ambiguous", "sam", "", elevenlabs.ErrAmbiguousVoice},
+		{"NotFound", "Nobody", "", elevenlabs.ErrVoiceNotFound},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			id, err := client.ResolveVoiceID(tc.voice)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("Expected error %v, got %v", tc.expectedErr, err)
+			}
+			if id != tc.expectedID {
+				t.Errorf("Expected voice ID %q, got %q", tc.expectedID, id)
+			}
+		})
+	}
+}
+
+func TestVoiceCache(t *testing.T) {
+	log := &requestLog{}
+	server := testServer(t, testServerConfig{routes: resolverRoutes, log: log})
+	defer server.Close()
+	client := elevenlabs.NewMockClient(context.Background(), server.URL, mockAPIKey, mockTimeout)
+
+	steps := []struct {
+		name         string
+		action       func() error
+		expectedGets int
+	}{
+		{"First lookup", func() error { _, err := client.FindVoice("Rachel"); return err }, 1},
+		{"Cached lookup", func() error { _, err := client.FindVoices(elevenlabs.VoiceQuery{}); return err }, 1},
+		{"DeleteVoice", func() error { return client.DeleteVoice("v1") }, 1},
+		{"Lookup after DeleteVoice", func() error { _, err := client.CachedVoices(); return err }, 2},
+		{"AddVoice", func() error { _, err := client.AddVoice(elevenlabs.AddEditVoiceRequest{Name: "New"}); return err }, 2},
+		{"Lookup after AddVoice", func() error { _, err := client.CachedVoices(); return err }, 3},
+		{"Invalidate", func() error { client.InvalidateVoiceCache(); _, err := client.CachedVoices(); return err }, 4},
+		{"Expired TTL", func() error {
+			client.SetVoiceCacheTTL(time.Millisecond)
+			time.Sleep(5 * time.Millisecond)
+			_, err := client.CachedVoices()
+			return err
+		}, 5},
+		{"Disabled", func() error { client.SetVoiceCacheTTL(0); _, err := client.CachedVoices(); return err }, 6},
+	}
+	for _, step := range steps {
+		if err := step.action(); err != nil {
+			t.Fatalf("%s: expected no errors, got %q", step.name, err)
+		}
+		if gets := log.count("GET /voices"); gets != step.expectedGets {
+			t.Errorf("%s: expected %d GetVoices requests, got %d", step.name, step.expectedGets, gets)
+		}
+	}
+}
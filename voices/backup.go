@@ -0,0 +1,186 @@
+package voices
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+
+	"github.com/haguro/elevenlabs-go"
+)
+
+// backupIndexName is the name of the archive entry holding the BackupIndex.
+const backupIndexName = "voices.json"
+
+// BackupAPI represents the functionality of the Elevenlabs API needed by Backup. It is satisfied by
+// *elevenlabs.Client.
+type BackupAPI interface {
+	GetVoices() ([]elevenlabs.Voice, error)
+	GetVoice(voiceId string, queries ...elevenlabs.QueryFunc) (elevenlabs.Voice, error)
+	GetSampleAudio(voiceId, sampleId string) ([]byte, error)
+}
+
+// RestoreAPI represents the functionality of the Elevenlabs API needed by Restore. It is satisfied by
+// *elevenlabs.Client.
+type RestoreAPI interface {
+	AddVoice(voiceReq elevenlabs.AddEditVoiceRequest) (string, error)
+	EditVoiceSettings(voiceId string, settings elevenlabs.VoiceSettings) error
+}
+
+// BackupIndex is the metadata stored in a backup archive.
+type BackupIndex struct {
+	Voices []BackupVoice `json:"voices"`
+}
+
+// BackupVoice is the metadata of a backed up voice.
+type BackupVoice struct {
+	VoiceID     string                   `json:"voice_id"`
+	Name        string                   `json:"name"`
+	Description string                   `json:"description,omitempty"`
+	Category    string                   `json:"category"`
+	Labels      map[string]string        `json:"labels,omitempty"`
+	Settings    elevenlabs.VoiceSettings `json:"settings"`
+	Samples     []BackupSample           `json:"samples"`
+}
+
+// BackupSample is the metadata of a backed up sample.
+type BackupSample struct {
+	SampleID string `json:"sample_id"`
+	FileName string `json:"file_name"`
+	MimeType string `json:"mime_type"`
+	// Path is the name of the archive entry holding the sample's audio.
+	Path string `json:"path"`
+}
+
+// Backup writes the cloned and generated voices of an account, including the audio of all their samples, to
+// a zip archive. Premade voices are not backed up.
+//
+// It takes a BackupAPI argument (typically an *elevenlabs.Client) and an io.Writer argument the archive is
+// written to.
+//
+// It returns the index of the archive or an error.
+func Backup(client BackupAPI, w io.Writer) (BackupIndex, error) {
+	voices, err := client.GetVoices()
+	if err != nil {
+		return BackupIndex{}, err
+	}
+	zw := zip.NewWriter(w)
+	var index BackupIndex
+	for _, v := range voices {
+		if v.Category == "premade" {
+			continue
+		}
+		full, err := client.GetVoice(v.VoiceId, elevenlabs.WithSettings())
+		if err != nil {
+			return BackupIndex{}, fmt.Errorf("voice %q: %w", v.Name, err)
+		}
+		bv := BackupVoice{
+			VoiceID:     full.VoiceId,
+			Name:        full.Name,
+			Description: full.Description,
+			Category:    full.Category,
+			Labels:      full.Labels,
+			Settings:    full.Settings,
+		}
+		for _, s := range full.Samples {
+			data, err := client.GetSampleAudio(full.VoiceId, s.SampleId)
+			if err != nil {
+				return BackupIndex{}, fmt.Errorf("voice %q sample %q: %w", v.Name, s.FileName, err)
+			}
+			entry := path.Join("samples", full.VoiceId, s.SampleId+path.Ext(s.FileName))
+			f, err := zw.Create(entry)
+			if err != nil {
+				return BackupIndex{}, err
+			}
+			if _, err := f.Write(data); err != nil {
+				return BackupIndex{}, err
+			}
+			bv.Samples = append(bv.Samples, BackupSample{SampleID: s.SampleId, FileName: s.FileName, MimeType: s.MimeType, Path: entry})
+		}
+		index.Voices = append(index.Voices, bv)
+	}
+
+	f, err := zw.Create(backupIndexName)
+	if err != nil {
+		return BackupIndex{}, err
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(index); err != nil {
+		return BackupIndex{}, err
+	}
+	return index, zw.Close()
+}
+
+// Restore recreates the voices of a backup archive with AddVoice and EditVoiceSettings, typically on a client
+// of another account. Voices without samples, such as generated voices, cannot be recreated and are skipped.
+//
+// It takes a RestoreAPI argument (typically an *elevenlabs.Client) and an io.ReaderAt argument of a given size
+// the archive is read from, such as an *os.File.
+//
+// It returns a map of the backed up voice IDs to the IDs of the recreated voices, and an error if the archive
+// is invalid or a voice could not be recreated. The map always contains the voices created before the error.
+func Restore(client RestoreAPI, r io.ReaderAt, size int64) (map[string]string, error) {
+	ids := map[string]string{}
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return ids, err
+	}
+	files := map[string]*zip.File{}
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+	indexFile, ok := files[backupIndexName]
+	if !ok {
+		return ids, fmt.Errorf("invalid backup archive: %s not found", backupIndexName)
+	}
+	rc, err := indexFile.Open()
+	if err != nil {
+		return ids, err
+	}
+	var index BackupIndex
+	err = json.NewDecoder(rc).Decode(&index)
+	rc.Close()
+	if err != nil {
+		return ids, fmt.Errorf("invalid backup archive: %w", err)
+	}
+
+	for _, v := range index.Voices {
+		if len(v.Samples) == 0 {
+			continue
+		}
+		newID, err := restoreVoice(client, files, v)
+		if newID != "" {
+			ids[v.VoiceID] = newID
+		}
+		if err != nil {
+			return ids, fmt.Errorf("voice %q: %w", v.Name, err)
+		}
+	}
+	return ids, nil
+}
+
+func restoreVoice(client RestoreAPI, files map[string]*zip.File, v BackupVoice) (string, error) {
+	req := elevenlabs.AddEditVoiceRequest{Name: v.Name, Description: v.Description, Labels: v.Labels}
+	for _, s := range v.Samples {
+		f, ok := files[s.Path]
+		if !ok {
+			return "", fmt.Errorf("sample %s not found in archive", s.Path)
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return "", err
+		}
+		defer rc.Close()
+		req.Files = append(req.Files, elevenlabs.VoiceFile{Name: s.FileName, MimeType: s.MimeType, Reader: rc})
+	}
+	id, err := client.AddVoice(req)
+	if err != nil {
+		return "", err
+	}
+	if err := client.EditVoiceSettings(id, v.Settings); err != nil {
+		return id, err
+	}
+	return id, nil
+}
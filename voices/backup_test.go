@@ -0,0 +1,82 @@
+package voices_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/haguro/elevenlabs-go"
+	"github.com/haguro/elevenlabs-go/voices"
+)
+
+func TestBackupRestore(t *testing.T) {
+	source := &fakeAPI{voices: []elevenlabs.Voice{
+		{VoiceId: "v1", Name: "Narrator", Category: "cloned", Description: "Deep", Labels: map[string]string{"accent": "british"},
+			Settings: elevenlabs.VoiceSettings{Stability: 0.4, SimilarityBoost: 0.8},
+			Samples: []elevenlabs.VoiceSample{
+				{SampleId: "s1", FileName: "intro.mp3", MimeType: "audio/mpeg"},
+				{SampleId: "s2", FileName: "outro.wav", MimeType: "audio/wav"},
+			}},
+		{VoiceId: "v2", Name: "Designed", Category: "generated"},
+		{VoiceId: "p1", Name: "Rachel", Category: "premade", Samples: []elevenlabs.VoiceSample{{SampleId: "s3"}}},
+	}}
+
+	archive := bytes.Buffer{}
+	index, err := voices.Backup(source, &archive)
+	if err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+	if len(index.Voices) != 2 || index.Voices[0].Settings.Stability != 0.4 || len(index.Voices[0].Samples) != 2 {
+		t.Fatalf("Unexpected backup index %+v", index)
+	}
+
+	target := &fakeAPI{}
+	ids, err := voices.Restore(target, bytes.NewReader(archive.Bytes()), int64(archive.Len()))
+	if err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+	if exp := map[string]string{"v1": "new1"}; !reflect.DeepEqual(ids, exp) {
+		t.Errorf("Expected ID map %v, got %v", exp, ids)
+	}
+	if len(target.addReqs) != 1 {
+		t.Fatalf("Expected one voice to be added, got %d", len(target.addReqs))
+	}
+	req := target.addReqs[0]
+	if req.Name != "Narrator" || req.Description != "Deep" || req.Labels["accent"] != "british" {
+		t.Errorf("Unexpected AddVoice request %+v", req)
+	}
+	expFiles := map[string]string{"intro.mp3": "audio/mpeg:audio-v1-s1", "outro.wav": "audio/wav:audio-v1-s2"}
+	if !reflect.DeepEqual(target.addFiles, expFiles) {
+		t.Errorf("Expected restored samples %v, got %v", expFiles, target.addFiles)
+	}
+	if exp := []string{"add Narrator 0", "settings new1 0.4"}; !reflect.DeepEqual(target.calls, exp) {
+		t.Errorf("Expected calls %v, got %v", exp, target.calls)
+	}
+}
+
+func TestBackupRestoreErrors(t *testing.T) {
+	source := &fakeAPI{failOn: "sample", voices: []elevenlabs.Voice{{VoiceId: "v1", Name: "A", Samples: []elevenlabs.VoiceSample{{SampleId: "s1"}}}}}
+	if _, err := voices.Backup(source, &bytes.Buffer{}); err == nil || !strings.Contains(err.Error(), `voice "A"`) {
+		t.Errorf("Expected an error naming the voice, got %v", err)
+	}
+
+	if _, err := voices.Restore(&fakeAPI{}, strings.NewReader("not a zip"), 9); err == nil {
+		t.Error("Expected an error for an invalid archive, got nil")
+	}
+
+	source.failOn = ""
+	archive := bytes.Buffer{}
+	if _, err := voices.Backup(source, &archive); err != nil {
+		t.Fatal(err)
+	}
+	target := &fakeAPI{failOn: "settings"}
+	ids, err := voices.Restore(target, bytes.NewReader(archive.Bytes()), int64(archive.Len()))
+	if err == nil || errors.Unwrap(err) == nil {
+		t.Errorf("Expected a wrapped error, got %v", err)
+	}
+	if ids["v1"] != "new1" {
+		t.Errorf("Expected the created voice to be in the ID map despite the error, got %v", ids)
+	}
+}
@@ -0,0 +1,97 @@
+// Package voices manages the cloned voices of an Elevenlabs account declaratively, from a manifest that
+// describes the voices that should exist. It can also back up voices to a zip archive and restore them,
+// for instance to migrate voices between accounts.
+package voices
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/haguro/elevenlabs-go"
+)
+
+// ErrDuplicateVoice is returned when a manifest, or the account it is compared with, holds several voices
+// with the same name.
+var ErrDuplicateVoice = errors.New("duplicate voice name")
+
+// Manifest describes the voices that should exist in an account.
+//
+// Manifests are stored as JSON. YAML is not supported to keep the module free of dependencies, but YAML
+// manifests with the same structure can be converted to JSON with common tools such as yq.
+type Manifest struct {
+	Voices []VoiceSpec `json:"voices"`
+}
+
+// VoiceSpec describes a single voice of a Manifest. Voices are identified by their name.
+type VoiceSpec struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	// Samples are the paths of the voice's sample files. Relative paths are resolved against the directory
+	// of the manifest by LoadManifest.
+	Samples []string `json:"samples,omitempty"`
+	// Settings, if set, are the default settings of the voice.
+	Settings *elevenlabs.VoiceSettings `json:"settings,omitempty"`
+}
+
+// ParseManifest reads and validates a JSON manifest. Unknown fields are rejected to catch typos.
+//
+// It returns the Manifest or an error.
+func ParseManifest(r io.Reader) (Manifest, error) {
+	var m Manifest
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&m); err != nil {
+		return Manifest{}, fmt.Errorf("invalid manifest: %w", err)
+	}
+	if err := m.Validate(); err != nil {
+		return Manifest{}, err
+	}
+	return m, nil
+}
+
+// LoadManifest reads a JSON manifest from a file, resolving the relative sample paths against the directory
+// of the file.
+//
+// It returns the Manifest or an error.
+func LoadManifest(path string) (Manifest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Manifest{}, err
+	}
+	defer f.Close()
+	m, err := ParseManifest(f)
+	if err != nil {
+		return Manifest{}, err
+	}
+	dir := filepath.Dir(path)
+	for i := range m.Voices {
+		for j, s := range m.Voices[i].Samples {
+			if !filepath.IsAbs(s) {
+				m.Voices[i].Samples[j] = filepath.Join(dir, s)
+			}
+		}
+	}
+	return m, nil
+}
+
+// Validate checks that every voice of the manifest has a unique, non-empty name.
+//
+// It returns nil if the manifest is valid or an error otherwise.
+func (m Manifest) Validate() error {
+	seen := map[string]bool{}
+	for i, v := range m.Voices {
+		if v.Name == "" {
+			return fmt.Errorf("invalid manifest: voice #%d has no name", i)
+		}
+		if seen[v.Name] {
+			return fmt.Errorf("invalid manifest: %w: %q", ErrDuplicateVoice, v.Name)
+		}
+		seen[v.Name] = true
+	}
+	return nil
+}
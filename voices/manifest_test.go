@@ -0,0 +1,57 @@
+package voices_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/haguro/elevenlabs-go/voices"
+)
+
+func TestLoadManifest(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "voices.json")
+	content := `{"voices":[{"name":"Narrator","labels":{"accent":"british"},"samples":["samples/a.mp3","/abs/b.mp3"],"settings":{"stability":0.5,"similarity_boost":0.75}}]}`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m, err := voices.LoadManifest(path)
+	if err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+	if len(m.Voices) != 1 || m.Voices[0].Name != "Narrator" || m.Voices[0].Settings == nil || m.Voices[0].Settings.Stability != 0.5 {
+		t.Fatalf("Unexpected manifest %+v", m)
+	}
+	if exp := filepath.Join(dir, "samples", "a.mp3"); m.Voices[0].Samples[0] != exp {
+		t.Errorf("Expected relative sample path to resolve to %q, got %q", exp, m.Voices[0].Samples[0])
+	}
+	if m.Voices[0].Samples[1] != "/abs/b.mp3" {
+		t.Errorf("Expected absolute sample path to be kept, got %q", m.Voices[0].Samples[1])
+	}
+}
+
+func TestParseManifestErrors(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+		expErr  error
+	}{
+		{"Unknown field", `{"voices":[{"name":"A","sample":["a.mp3"]}]}`, nil},
+		{"Missing name", `{"voices":[{"description":"no name"}]}`, nil},
+		{"Duplicate names", `{"voices":[{"name":"A"},{"name":"A"}]}`, voices.ErrDuplicateVoice},
+		{"Not JSON", `voices: []`, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := voices.ParseManifest(strings.NewReader(tc.content))
+			if err == nil {
+				t.Fatal("Expected an error, got nil")
+			}
+			if tc.expErr != nil && !errors.Is(err, tc.expErr) {
+				t.Errorf("Expected error %q, got %q", tc.expErr, err)
+			}
+		})
+	}
+}
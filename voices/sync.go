@@ -0,0 +1,319 @@
+package voices
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/haguro/elevenlabs-go"
+)
+
+// API represents the voice management functionality of the Elevenlabs API. It is satisfied by
+// *elevenlabs.Client.
+type API interface {
+	GetVoices() ([]elevenlabs.Voice, error)
+	GetVoice(voiceId string, queries ...elevenlabs.QueryFunc) (elevenlabs.Voice, error)
+	AddVoice(voiceReq elevenlabs.AddEditVoiceRequest) (string, error)
+	EditVoice(voiceId string, voiceReq elevenlabs.AddEditVoiceRequest) error
+	EditVoiceSettings(voiceId string, settings elevenlabs.VoiceSettings) error
+	DeleteSample(voiceId, sampleId string) error
+	DeleteVoice(voiceId string) error
+}
+
+// ActionType represents the kind of change an Action makes.
+type ActionType string
+
+const (
+	ActionAddVoice          ActionType = "add_voice"
+	ActionEditVoice         ActionType = "edit_voice"
+	ActionEditVoiceSettings ActionType = "edit_voice_settings"
+	ActionDeleteSample      ActionType = "delete_sample"
+	ActionDeleteVoice       ActionType = "delete_voice"
+)
+
+// Action represents a single API call of a Plan.
+type Action struct {
+	Type      ActionType
+	VoiceName string
+	// VoiceID is the ID of the voice the action applies to. It is empty for voices that are added by the plan.
+	VoiceID string
+	// SampleID is the ID of the sample deleted by ActionDeleteSample.
+	SampleID string
+	// Files are the sample files uploaded by ActionAddVoice and ActionEditVoice.
+	Files []string
+	// Reason is a human readable explanation of the action.
+	Reason string
+
+	spec *VoiceSpec
+}
+
+func (a Action) String() string {
+	target := fmt.Sprintf("%q", a.VoiceName)
+	if a.VoiceID != "" {
+		target += " (" + a.VoiceID + ")"
+	}
+	s := fmt.Sprintf("%s %s", a.Type, target)
+	if a.SampleID != "" {
+		s += " sample " + a.SampleID
+	}
+	if a.Reason != "" {
+		s += ": " + a.Reason
+	}
+	return s
+}
+
+// Plan is the ordered list of actions that brings an account in line with a manifest.
+type Plan struct {
+	Actions []Action
+}
+
+// Empty reports whether the plan has no actions, i.e. the account already matches the manifest.
+func (p Plan) Empty() bool {
+	return len(p.Actions) == 0
+}
+
+// String returns the actions of the plan, one per line, which is suitable as dry run output.
+func (p Plan) String() string {
+	if p.Empty() {
+		return "no changes\n"
+	}
+	b := strings.Builder{}
+	for _, a := range p.Actions {
+		b.WriteString(a.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Syncer computes and applies the changes that make the voices of an account match a manifest.
+//
+// Voices are matched by name and only cloned and generated voices are managed; premade voices are ignored.
+//
+// The NewSyncer function should be used when instantiating a new Syncer.
+type Syncer struct {
+	// Prune deletes the managed voices of the account that are not in the manifest.
+	Prune bool
+
+	client API
+}
+
+// NewSyncer creates and returns a new Syncer.
+//
+// It takes an API argument (typically an *elevenlabs.Client) used to retrieve and change the voices.
+func NewSyncer(client API) *Syncer {
+	return &Syncer{client: client}
+}
+
+// Plan compares a manifest with the voices of the account.
+//
+// Samples are compared by file name and by the MD5 hash of their content, which the API reports for each
+// sample. Remote samples that are not in the manifest are deleted, and local samples that are missing remotely
+// are uploaded with EditVoice.
+//
+// It returns the Plan or an error if the voices could not be retrieved or a sample file could not be read.
+func (s *Syncer) Plan(m Manifest) (Plan, error) {
+	if err := m.Validate(); err != nil {
+		return Plan{}, err
+	}
+	voices, err := s.client.GetVoices()
+	if err != nil {
+		return Plan{}, err
+	}
+	remote := map[string]elevenlabs.Voice{}
+	for _, v := range voices {
+		if v.Category == "premade" {
+			continue
+		}
+		if _, ok := remote[v.Name]; ok {
+			return Plan{}, fmt.Errorf("%w in account: %q", ErrDuplicateVoice, v.Name)
+		}
+		remote[v.Name] = v
+	}
+
+	var plan Plan
+	for i := range m.Voices {
+		spec := &m.Voices[i]
+		v, ok := remote[spec.Name]
+		if !ok {
+			plan.Actions = append(plan.Actions, Action{Type: ActionAddVoice, VoiceName: spec.Name, Files: spec.Samples, Reason: "voice not found", spec: spec})
+			if spec.Settings != nil {
+				plan.Actions = append(plan.Actions, Action{Type: ActionEditVoiceSettings, VoiceName: spec.Name, Reason: "settings of new voice", spec: spec})
+			}
+			continue
+		}
+		delete(remote, spec.Name)
+		actions, err := s.diff(spec, v)
+		if err != nil {
+			return Plan{}, err
+		}
+		plan.Actions = append(plan.Actions, actions...)
+	}
+
+	if s.Prune {
+		for _, v := range voices {
+			if _, ok := remote[v.Name]; ok && v.Category != "premade" {
+				plan.Actions = append(plan.Actions, Action{Type: ActionDeleteVoice, VoiceName: v.Name, VoiceID: v.VoiceId, Reason: "not in manifest"})
+			}
+		}
+	}
+	return plan, nil
+}
+
+// diff returns the actions that make an existing voice match its spec.
+func (s *Syncer) diff(spec *VoiceSpec, v elevenlabs.Voice) ([]Action, error) {
+	var actions []Action
+	remoteSamples := map[string]elevenlabs.VoiceSample{}
+	for _, rs := range v.Samples {
+		remoteSamples[rs.FileName] = rs
+	}
+
+	var newFiles []string
+	for _, path := range spec.Samples {
+		hash, err := fileHash(path)
+		if err != nil {
+			return nil, err
+		}
+		name := filepath.Base(path)
+		if rs, ok := remoteSamples[name]; ok && strings.EqualFold(rs.Hash, hash) {
+			delete(remoteSamples, name)
+			continue
+		}
+		newFiles = append(newFiles, path)
+	}
+	// Stale samples are deleted first so that the voice never exceeds the sample limit.
+	for _, rs := range v.Samples {
+		if _, ok := remoteSamples[rs.FileName]; ok {
+			actions = append(actions, Action{Type: ActionDeleteSample, VoiceName: v.Name, VoiceID: v.VoiceId, SampleID: rs.SampleId, Reason: fmt.Sprintf("sample %q not in manifest", rs.FileName)})
+		}
+	}
+
+	var reasons []string
+	if spec.Description != v.Description {
+		reasons = append(reasons, "description changed")
+	}
+	if !labelsEqual(spec.Labels, v.Labels) {
+		reasons = append(reasons, "labels changed")
+	}
+	if len(newFiles) > 0 {
+		reasons = append(reasons, fmt.Sprintf("%d new samples", len(newFiles)))
+	}
+	if len(reasons) > 0 {
+		actions = append(actions, Action{Type: ActionEditVoice, VoiceName: v.Name, VoiceID: v.VoiceId, Files: newFiles, Reason: strings.Join(reasons, ", "), spec: spec})
+	}
+
+	if spec.Settings != nil {
+		withSettings, err := s.client.GetVoice(v.VoiceId, elevenlabs.WithSettings())
+		if err != nil {
+			return nil, err
+		}
+		if !settingsMatch(*spec.Settings, withSettings.Settings) {
+			actions = append(actions, Action{Type: ActionEditVoiceSettings, VoiceName: v.Name, VoiceID: v.VoiceId, Reason: "settings changed", spec: spec})
+		}
+	}
+	return actions, nil
+}
+
+func labelsEqual(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if bv, ok := b[k]; !ok || bv != v {
+			return false
+		}
+	}
+	return true
+}
+
+// settingsMatch reports whether the current settings of a voice match the desired ones. Style and SpeakerBoost
+// are only compared when they are set in the desired settings.
+func settingsMatch(want, got elevenlabs.VoiceSettings) bool {
+	if want.Stability != got.Stability || want.SimilarityBoost != got.SimilarityBoost {
+		return false
+	}
+	if want.Style != nil && (got.Style == nil || *got.Style != *want.Style) {
+		return false
+	}
+	if want.SpeakerBoost != nil && (got.SpeakerBoost == nil || *got.SpeakerBoost != *want.SpeakerBoost) {
+		return false
+	}
+	return true
+}
+
+// Apply performs the actions of a plan in order. The IDs of voices added by the plan are used by the
+// actions that follow.
+//
+// It returns nil if all actions succeeded, or an error that wraps the error of the first failed action, in
+// which case the remaining actions are not performed. Applying a fresh plan resumes the sync.
+func (s *Syncer) Apply(plan Plan) error {
+	added := map[string]string{}
+	for _, a := range plan.Actions {
+		id := a.VoiceID
+		if id == "" {
+			id = added[a.VoiceName]
+		}
+		var err error
+		switch a.Type {
+		case ActionAddVoice:
+			id, err = s.client.AddVoice(a.request())
+			added[a.VoiceName] = id
+		case ActionEditVoice:
+			err = s.client.EditVoice(id, a.request())
+		case ActionEditVoiceSettings:
+			if a.spec == nil || a.spec.Settings == nil {
+				err = fmt.Errorf("no settings")
+			} else {
+				err = s.client.EditVoiceSettings(id, *a.spec.Settings)
+			}
+		case ActionDeleteSample:
+			err = s.client.DeleteSample(id, a.SampleID)
+		case ActionDeleteVoice:
+			err = s.client.DeleteVoice(id)
+		default:
+			err = fmt.Errorf("unknown action type %q", a.Type)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", a, err)
+		}
+	}
+	return nil
+}
+
+func (a Action) request() elevenlabs.AddEditVoiceRequest {
+	req := elevenlabs.AddEditVoiceRequest{Name: a.VoiceName, FilePaths: a.Files}
+	if a.spec != nil {
+		req.Description = a.spec.Description
+		req.Labels = a.spec.Labels
+	}
+	return req
+}
+
+// Sync computes the plan that makes the account match a manifest and applies it unless dryRun is true.
+//
+// It returns the computed Plan, which can be printed to show the changes, and an error if the plan could not
+// be computed or applied.
+func (s *Syncer) Sync(m Manifest, dryRun bool) (Plan, error) {
+	plan, err := s.Plan(m)
+	if err != nil || dryRun {
+		return plan, err
+	}
+	return plan, s.Apply(plan)
+}
+
+// fileHash returns the hex encoded MD5 hash of the content of a file.
+func fileHash(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := md5.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,196 @@
+package voices_test
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/haguro/elevenlabs-go"
+	"github.com/haguro/elevenlabs-go/voices"
+)
+
+// fakeAPI keeps voices in memory and records the calls that change them.
+type fakeAPI struct {
+	voices  []elevenlabs.Voice
+	calls   []string
+	nextID  int
+	failOn  string
+	addReqs []elevenlabs.AddEditVoiceRequest
+	// addFiles holds the content of the Files of AddVoice requests by file name.
+	addFiles map[string]string
+}
+
+func (f *fakeAPI) record(call string) error {
+	f.calls = append(f.calls, call)
+	if f.failOn != "" && strings.HasPrefix(call, f.failOn) {
+		return errors.New("api failure")
+	}
+	return nil
+}
+
+func (f *fakeAPI) GetVoices() ([]elevenlabs.Voice, error) {
+	vs := make([]elevenlabs.Voice, len(f.voices))
+	for i, v := range f.voices {
+		v.Settings = elevenlabs.VoiceSettings{} // GetVoices does not include settings.
+		vs[i] = v
+	}
+	return vs, nil
+}
+
+func (f *fakeAPI) GetVoice(voiceId string, queries ...elevenlabs.QueryFunc) (elevenlabs.Voice, error) {
+	for _, v := range f.voices {
+		if v.VoiceId == voiceId {
+			return v, nil
+		}
+	}
+	return elevenlabs.Voice{}, errors.New("voice not found")
+}
+
+func (f *fakeAPI) AddVoice(req elevenlabs.AddEditVoiceRequest) (string, error) {
+	f.nextID++
+	id := fmt.Sprintf("new%d", f.nextID)
+	f.addReqs = append(f.addReqs, req)
+	for _, file := range req.Files {
+		b, err := io.ReadAll(file.Reader)
+		if err != nil {
+			return "", err
+		}
+		if f.addFiles == nil {
+			f.addFiles = map[string]string{}
+		}
+		f.addFiles[file.Name] = file.MimeType + ":" + string(b)
+	}
+	return id, f.record(fmt.Sprintf("add %s %d", req.Name, len(req.FilePaths)))
+}
+
+func (f *fakeAPI) EditVoice(voiceId string, req elevenlabs.AddEditVoiceRequest) error {
+	return f.record(fmt.Sprintf("edit %s %d", voiceId, len(req.FilePaths)))
+}
+
+func (f *fakeAPI) EditVoiceSettings(voiceId string, settings elevenlabs.VoiceSettings) error {
+	return f.record(fmt.Sprintf("settings %s %v", voiceId, settings.Stability))
+}
+
+func (f *fakeAPI) DeleteSample(voiceId, sampleId string) error {
+	return f.record(fmt.Sprintf("delete-sample %s %s", voiceId, sampleId))
+}
+
+func (f *fakeAPI) GetSampleAudio(voiceId, sampleId string) ([]byte, error) {
+	if f.failOn == "sample" {
+		return nil, errors.New("api failure")
+	}
+	return []byte("audio-" + voiceId + "-" + sampleId), nil
+}
+
+func (f *fakeAPI) DeleteVoice(voiceId string) error {
+	return f.record("delete " + voiceId)
+}
+
+func writeSample(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func md5Hex(content string) string {
+	h := md5.Sum([]byte(content))
+	return hex.EncodeToString(h[:])
+}
+
+func TestSyncer(t *testing.T) {
+	dir := t.TempDir()
+	a := writeSample(t, dir, "a.mp3", "aaaa")
+	b := writeSample(t, dir, "b.mp3", "bbbbbb")
+	c := writeSample(t, dir, "c.mp3", "cc")
+
+	manifest := voices.Manifest{Voices: []voices.VoiceSpec{
+		{Name: "Unchanged", Description: "same", Samples: []string{a}, Settings: &elevenlabs.VoiceSettings{Stability: 0.5}},
+		{Name: "Changed", Labels: map[string]string{"accent": "irish"}, Samples: []string{a, b}, Settings: &elevenlabs.VoiceSettings{Stability: 0.3}},
+		{Name: "New", Samples: []string{b, c}, Settings: &elevenlabs.VoiceSettings{Stability: 0.9}},
+	}}
+	api := &fakeAPI{voices: []elevenlabs.Voice{
+		{VoiceId: "v1", Name: "Unchanged", Category: "cloned", Description: "same", Samples: []elevenlabs.VoiceSample{{SampleId: "s1", FileName: "a.mp3", SizeBytes: 4, Hash: md5Hex("aaaa")}},
+			Settings: elevenlabs.VoiceSettings{Stability: 0.5, Style: elevenlabs.Float32(0.2), SpeakerBoost: elevenlabs.Bool(true)}},
+		{VoiceId: "v2", Name: "Changed", Category: "cloned", Labels: map[string]string{"accent": "british"}, Samples: []elevenlabs.VoiceSample{
+			{SampleId: "s2", FileName: "a.mp3", SizeBytes: 4, Hash: md5Hex("aaaa")},
+			{SampleId: "s3", FileName: "b.mp3", SizeBytes: 6, Hash: md5Hex("bbbbbc")}, // content changed, same size
+			{SampleId: "s4", FileName: "old.mp3", SizeBytes: 10, Hash: md5Hex("old")},
+		}, Settings: elevenlabs.VoiceSettings{Stability: 0.5}},
+		{VoiceId: "v3", Name: "Stale", Category: "generated"},
+		{VoiceId: "p1", Name: "Rachel", Category: "premade"},
+	}}
+
+	syncer := voices.NewSyncer(api)
+	syncer.Prune = true
+	plan, err := syncer.Sync(manifest, true)
+	if err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+	if len(api.calls) != 0 {
+		t.Errorf("Expected no changes in dry run mode, got %v", api.calls)
+	}
+	var types []voices.ActionType
+	for _, a := range plan.Actions {
+		types = append(types, a.Type)
+	}
+	expTypes := []voices.ActionType{
+		voices.ActionDeleteSample, voices.ActionDeleteSample, voices.ActionEditVoice, voices.ActionEditVoiceSettings,
+		voices.ActionAddVoice, voices.ActionEditVoiceSettings,
+		voices.ActionDeleteVoice,
+	}
+	if !reflect.DeepEqual(types, expTypes) {
+		t.Errorf("Expected actions %v, got:\n%s", expTypes, plan)
+	}
+	if out := plan.String(); !strings.Contains(out, `edit_voice "Changed" (v2): labels changed, 1 new samples`) || !strings.Contains(out, `delete_voice "Stale" (v3): not in manifest`) {
+		t.Errorf("Unexpected plan output:\n%s", out)
+	}
+
+	if _, err := syncer.Sync(manifest, false); err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+	expCalls := []string{
+		"delete-sample v2 s3", "delete-sample v2 s4", "edit v2 1", "settings v2 0.3",
+		"add New 2", "settings new1 0.9",
+		"delete v3",
+	}
+	if !reflect.DeepEqual(api.calls, expCalls) {
+		t.Errorf("Expected calls %v, got %v", expCalls, api.calls)
+	}
+
+	syncer.Prune = false
+	plan, err = syncer.Plan(voices.Manifest{Voices: manifest.Voices[:1]})
+	if err != nil || !plan.Empty() || plan.String() != "no changes\n" {
+		t.Errorf("Expected an empty plan, got %v (error: %v)", plan, err)
+	}
+}
+
+func TestSyncerErrors(t *testing.T) {
+	api := &fakeAPI{voices: []elevenlabs.Voice{{VoiceId: "v1", Name: "A"}, {VoiceId: "v2", Name: "A"}}}
+	if _, err := voices.NewSyncer(api).Plan(voices.Manifest{}); !errors.Is(err, voices.ErrDuplicateVoice) {
+		t.Errorf("Expected error %q, got %v", voices.ErrDuplicateVoice, err)
+	}
+
+	api = &fakeAPI{failOn: "add"}
+	_, err := voices.NewSyncer(api).Sync(voices.Manifest{Voices: []voices.VoiceSpec{{Name: "A", Settings: &elevenlabs.VoiceSettings{}}}}, false)
+	if err == nil || !strings.Contains(err.Error(), `add_voice "A"`) {
+		t.Errorf("Expected an error naming the failed action, got %v", err)
+	}
+	if len(api.calls) != 1 {
+		t.Errorf("Expected the remaining actions to be skipped, got %v", api.calls)
+	}
+
+	api = &fakeAPI{voices: []elevenlabs.Voice{{VoiceId: "v1", Name: "A", Category: "cloned"}}}
+	if _, err := voices.NewSyncer(api).Plan(voices.Manifest{Voices: []voices.VoiceSpec{{Name: "A", Samples: []string{"missing.mp3"}}}}); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected error %q, got %v", os.ErrNotExist, err)
+	}
+}
@@ -0,0 +1,159 @@
+package elevenlabs
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Validate checks that Stability, SimilarityBoost and Style, if set, are within the range 0 to 1.
+//
+// It returns nil if the settings are valid or an error wrapping ErrInvalidVoiceSettings otherwise.
+func (s VoiceSettings) Validate() error {
+	var problems []string
+	check := func(name string, v float32) {
+		if math.IsNaN(float64(v)) || v < 0 || v > 1 {
+			problems = append(problems, fmt.Sprintf("%s %g is outside the range 0-1", name, v))
+		}
+	}
+	check("stability", s.Stability)
+	check("similarity_boost", s.SimilarityBoost)
+	if s.Style != nil {
+		check("style", *s.Style)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidVoiceSettings, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// VoiceSettingsOverride represents changes to be layered on top of existing voice settings with Merge. Nil
+// fields leave the corresponding setting unchanged.
+type VoiceSettingsOverride struct {
+	SimilarityBoost *float32
+	Stability       *float32
+	Style           *float32
+	SpeakerBoost    *bool
+}
+
+// Merge returns a copy of the settings with the non-nil fields of an override applied. The pointer fields of the
+// returned settings are fresh copies, so they alias neither the settings nor the override.
+func (s VoiceSettings) Merge(o VoiceSettingsOverride) VoiceSettings {
+	s.Style = copyPtr(s.Style)
+	s.SpeakerBoost = copyPtr(s.SpeakerBoost)
+	if o.SimilarityBoost != nil {
+		s.SimilarityBoost = *o.SimilarityBoost
+	}
+	if o.Stability != nil {
+		s.Stability = *o.Stability
+	}
+	if o.Style != nil {
+		s.Style = copyPtr(o.Style)
+	}
+	if o.SpeakerBoost != nil {
+		s.SpeakerBoost = copyPtr(o.SpeakerBoost)
+	}
+	return s
+}
+
+// Merge returns an override that applies the non-nil fields of o on top of the override.
+func (o VoiceSettingsOverride) Merge(next VoiceSettingsOverride) VoiceSettingsOverride {
+	merged := VoiceSettingsOverride{
+		SimilarityBoost: copyPtr(o.SimilarityBoost),
+		Stability:       copyPtr(o.Stability),
+		Style:           copyPtr(o.Style),
+		SpeakerBoost:    copyPtr(o.SpeakerBoost),
+	}
+	if next.SimilarityBoost != nil {
+		merged.SimilarityBoost = copyPtr(next.SimilarityBoost)
+	}
+	if next.Stability != nil {
+		merged.Stability = copyPtr(next.Stability)
+	}
+	if next.Style != nil {
+		merged.Style = copyPtr(next.Style)
+	}
+	if next.SpeakerBoost != nil {
+		merged.SpeakerBoost = copyPtr(next.SpeakerBoost)
+	}
+	return merged
+}
+
+func copyPtr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
+
+// MergeVoiceSettings retrieves the settings of a voice, or the default voice settings, and layers an override
+// on top of them, typically to build the VoiceSettings of a TextToSpeechRequest that only changes some of the
+// settings of the voice.
+//
+// It takes a string argument that represents the ID of the voice, or an empty string to use the default voice
+// settings, and a VoiceSettingsOverride argument.
+//
+// It returns the merged VoiceSettings, an error wrapping ErrInvalidVoiceSettings if they are out of range, or
+// an error if the settings could not be retrieved.
+func (c *Client) MergeVoiceSettings(voiceId string, o VoiceSettingsOverride) (VoiceSettings, error) {
+	var base VoiceSettings
+	var err error
+	if voiceId == "" {
+		base, err = c.GetDefaultVoiceSettings()
+	} else {
+		base, err = c.GetVoiceSettings(voiceId)
+	}
+	if err != nil {
+		return VoiceSettings{}, err
+	}
+	merged := base.Merge(o)
+	if err := merged.Validate(); err != nil {
+		return VoiceSettings{}, err
+	}
+	return merged, nil
+}
+
+// VoiceSettingsPreset represents a named set of voice settings suited to a kind of content.
+type VoiceSettingsPreset string
+
+const (
+	// PresetNarration favours consistent delivery for long-form content such as audiobooks.
+	PresetNarration VoiceSettingsPreset = "narration"
+	// PresetConversational balances consistency and variation for dialogue.
+	PresetConversational VoiceSettingsPreset = "conversational"
+	// PresetExpressive favours variation and exaggerated style for characters and dramatic content.
+	PresetExpressive VoiceSettingsPreset = "expressive"
+)
+
+var voiceSettingsPresets = map[VoiceSettingsPreset]VoiceSettingsOverride{
+	PresetNarration:      {Stability: Float32(0.7), SimilarityBoost: Float32(0.75), Style: Float32(0), SpeakerBoost: Bool(true)},
+	PresetConversational: {Stability: Float32(0.5), SimilarityBoost: Float32(0.75), Style: Float32(0.2), SpeakerBoost: Bool(true)},
+	PresetExpressive:     {Stability: Float32(0.3), SimilarityBoost: Float32(0.8), Style: Float32(0.6), SpeakerBoost: Bool(true)},
+}
+
+// VoiceSettingsPresets returns all the known voice settings presets sorted by name.
+func VoiceSettingsPresets() []VoiceSettingsPreset {
+	presets := make([]VoiceSettingsPreset, 0, len(voiceSettingsPresets))
+	for p := range voiceSettingsPresets {
+		presets = append(presets, p)
+	}
+	sort.Slice(presets, func(i, j int) bool { return presets[i] < presets[j] })
+	return presets
+}
+
+// Settings returns the voice settings of the preset, and a bool that is false if the preset is unknown.
+func (p VoiceSettingsPreset) Settings() (VoiceSettings, bool) {
+	o, ok := voiceSettingsPresets[p]
+	if !ok {
+		return VoiceSettings{}, false
+	}
+	return VoiceSettings{}.Merge(o), true
+}
+
+// Override returns the preset as a VoiceSettingsOverride, so that it can be layered on top of the settings of a
+// voice with Merge or MergeVoiceSettings. It returns an empty override if the preset is unknown.
+func (p VoiceSettingsPreset) Override() VoiceSettingsOverride {
+	return VoiceSettingsOverride{}.Merge(voiceSettingsPresets[p])
+}
@@ -0,0 +1,149 @@
+package elevenlabs_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/haguro/elevenlabs-go"
+)
+
+func TestVoiceSettingsJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings elevenlabs.VoiceSettings
+		expected string
+	}{
+		{"Unset", elevenlabs.VoiceSettings{Stability: 0.5, SimilarityBoost: 0.75},
+			`{"similarity_boost":0.75,"stability":0.5}`},
+		{"ExplicitZero", elevenlabs.VoiceSettings{Stability: 0.5, SimilarityBoost: 0.75, Style: elevenlabs.Float32(0), SpeakerBoost: elevenlabs.Bool(false)},
+			`{"similarity_boost":0.75,"stability":0.5,"style":0,"use_speaker_boost":false}`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := json.Marshal(tc.settings)
+			if err != nil {
+				t.Fatalf("Expected no errors, got %q", err)
+			}
+			if string(b) != tc.expected {
+				t.Errorf("Expected %s, got %s", tc.expected, b)
+			}
+		})
+	}
+}
+
+func TestVoiceSettingsValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings elevenlabs.VoiceSettings
+		valid    bool
+	}{
+		{"Valid", elevenlabs.VoiceSettings{Stability: 1, SimilarityBoost: 0, Style: elevenlabs.Float32(0.5)}, true},
+		{"Stability", elevenlabs.VoiceSettings{Stability: 1.5}, false},
+		{"SimilarityBoost", elevenlabs.VoiceSettings{SimilarityBoost: -0.1}, false},
+		{"Style", elevenlabs.VoiceSettings{Style: elevenlabs.Float32(2)}, false},
+		{"NaN", elevenlabs.VoiceSettings{Stability: float32(math.NaN())}, false},
+		{"NaNStyle", elevenlabs.VoiceSettings{Style: elevenlabs.Float32(float32(math.NaN()))}, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.settings.Validate()
+			if tc.valid && err != nil {
+				t.Errorf("Expected no errors, got %q", err)
+			}
+			if !tc.valid && !errors.Is(err, elevenlabs.ErrInvalidVoiceSettings) {
+				t.Errorf("Expected error %v, got %v", elevenlabs.ErrInvalidVoiceSettings, err)
+			}
+		})
+	}
+}
+
+func TestVoiceSettingsPresets(t *testing.T) {
+	presets := elevenlabs.VoiceSettingsPresets()
+	expected := []elevenlabs.VoiceSettingsPreset{elevenlabs.PresetConversational, elevenlabs.PresetExpressive, elevenlabs.PresetNarration}
+	if !reflect.DeepEqual(presets, expected) {
+		t.Fatalf("Expected presets %v, got %v", expected, presets)
+	}
+	for _, p := range presets {
+		s, ok := p.Settings()
+		if !ok {
+			t.Fatalf("Expected preset %q to be known", p)
+		}
+		if err := s.Validate(); err != nil {
+			t.Errorf("Expected preset %q to be valid, got %q", p, err)
+		}
+		if s.Style == nil || s.SpeakerBoost == nil {
+			t.Errorf("Expected preset %q to set all the settings, got %+v", p, s)
+		}
+	}
+	if _, ok := elevenlabs.VoiceSettingsPreset("unknown").Settings(); ok {
+		t.Errorf("Expected unknown preset not to be found")
+	}
+
+	o := elevenlabs.PresetNarration.Override()
+	*o.Stability = 0
+	if s, _ := elevenlabs.PresetNarration.Settings(); s.Stability == 0 {
+		t.Errorf("Expected changes to an override not to affect the preset")
+	}
+}
+
+func TestVoiceSettingsMergeCopiesPointers(t *testing.T) {
+	settings := elevenlabs.VoiceSettings{Style: elevenlabs.Float32(0.3), SpeakerBoost: elevenlabs.Bool(true)}
+	override := elevenlabs.VoiceSettingsOverride{Style: elevenlabs.Float32(0.6)}
+	merged := settings.Merge(override)
+	*override.Style = 0.9
+	*settings.SpeakerBoost = false
+	if *merged.Style != 0.6 || !*merged.SpeakerBoost {
+		t.Errorf("Expected merged settings to be unaffected by later changes, got style %g and speaker boost %t", *merged.Style, *merged.SpeakerBoost)
+	}
+}
+
+func TestMergeVoiceSettings(t *testing.T) {
+	server := testServer(t, testServerConfig{
+		expectedMethod:      http.MethodGet,
+		expectedContentType: contentTypeJSON,
+		expectedAccept:      "*/*",
+		statusCode:          http.StatusOK,
+		responseBody:        []byte(`{"similarity_boost":0.75,"stability":0.5,"style":0.3,"use_speaker_boost":true}`),
+	})
+	defer server.Close()
+	client := elevenlabs.NewMockClient(context.Background(), server.URL, mockAPIKey, mockTimeout)
+
+	tests := []struct {
+		name     string
+		override elevenlabs.VoiceSettingsOverride
+		expected elevenlabs.VoiceSettings
+		valid    bool
+	}{
+		{"NoOverride", elevenlabs.VoiceSettingsOverride{},
+			elevenlabs.VoiceSettings{SimilarityBoost: 0.75, Stability: 0.5, Style: elevenlabs.Float32(0.3), SpeakerBoost: elevenlabs.Bool(true)}, true},
+		{"ExplicitZero", elevenlabs.VoiceSettingsOverride{Style: elevenlabs.Float32(0), SpeakerBoost: elevenlabs.Bool(false)},
+			elevenlabs.VoiceSettings{SimilarityBoost: 0.75, Stability: 0.5, Style: elevenlabs.Float32(0), SpeakerBoost: elevenlabs.Bool(false)}, true},
+		{"PresetWithOverride", elevenlabs.PresetExpressive.Override().Merge(elevenlabs.VoiceSettingsOverride{Stability: elevenlabs.Float32(0.4)}),
+			elevenlabs.VoiceSettings{SimilarityBoost: 0.8, Stability: 0.4, Style: elevenlabs.Float32(0.6), SpeakerBoost: elevenlabs.Bool(true)}, true},
+		{"OutOfRange", elevenlabs.VoiceSettingsOverride{Stability: elevenlabs.Float32(3)}, elevenlabs.VoiceSettings{}, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, voiceId := range []string{"", "TestVoiceID"} {
+				s, err := client.MergeVoiceSettings(voiceId, tc.override)
+				if !tc.valid {
+					if !errors.Is(err, elevenlabs.ErrInvalidVoiceSettings) {
+						t.Errorf("Expected error %v, got %v", elevenlabs.ErrInvalidVoiceSettings, err)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("Expected no errors, got %q", err)
+				}
+				if !reflect.DeepEqual(s, tc.expected) {
+					t.Errorf("Expected settings %+v, got %+v", tc.expected, s)
+				}
+			}
+		})
+	}
+}
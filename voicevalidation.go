@@ -0,0 +1,297 @@
+package elevenlabs
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/haguro/elevenlabs-go/audio"
+)
+
+// VoiceSampleLimits represents the limits that the samples and labels of an AddEditVoiceRequest are validated
+// against. A zero value disables the corresponding check.
+type VoiceSampleLimits struct {
+	// MaxSamples is the maximum number of samples of a request.
+	MaxSamples int
+	// MaxFileSize is the maximum size of a single sample in bytes.
+	MaxFileSize int64
+	// MaxTotalSize is the maximum combined size of all the samples in bytes.
+	MaxTotalSize int64
+	// MinDuration and MaxDuration restrict the duration of each MP3 or WAV sample.
+	MinDuration time.Duration
+	MaxDuration time.Duration
+	// MaxLabels is the maximum number of labels.
+	MaxLabels int
+	// MaxLabelLength is the maximum length of label keys and values.
+	MaxLabelLength int
+}
+
+// DefaultVoiceSampleLimits reflects the limits of instant voice cloning at the time of writing.
+var DefaultVoiceSampleLimits = VoiceSampleLimits{
+	MaxSamples:     25,
+	MaxFileSize:    10 << 20,
+	MinDuration:    time.Second,
+	MaxLabels:      5,
+	MaxLabelLength: 50,
+}
+
+// Sample formats returned by DetectSampleFormat.
+const (
+	SampleFormatMP3  = "mp3"
+	SampleFormatWAV  = "wav"
+	SampleFormatFLAC = "flac"
+	SampleFormatOgg  = "ogg"
+	SampleFormatMP4  = "mp4"
+	SampleFormatWebM = "webm"
+	SampleFormatAAC  = "aac"
+)
+
+// sniffLen is the number of bytes DetectSampleFormat needs at most.
+const sniffLen = 12
+
+// DetectSampleFormat returns the audio format of a sample based on the magic bytes at its start, or an empty
+// string if the format is not one that is accepted for voice samples.
+func DetectSampleFormat(header []byte) string {
+	switch {
+	case len(header) >= 3 && string(header[:3]) == "ID3":
+		return SampleFormatMP3
+	case len(header) >= 12 && string(header[:4]) == "RIFF" && string(header[8:12]) == "WAVE":
+		return SampleFormatWAV
+	case len(header) >= 4 && string(header[:4]) == "fLaC":
+		return SampleFormatFLAC
+	case len(header) >= 4 && string(header[:4]) == "OggS":
+		return SampleFormatOgg
+	case len(header) >= 8 && string(header[4:8]) == "ftyp":
+		return SampleFormatMP4
+	case len(header) >= 4 && bytes.Equal(header[:4], []byte{0x1A, 0x45, 0xDF, 0xA3}):
+		return SampleFormatWebM
+	case len(header) >= 2 && header[0] == 0xFF && header[1]&0xF6 == 0xF0:
+		// ADTS frames have the layer bits set to 0.
+		return SampleFormatAAC
+	case len(header) >= 2 && header[0] == 0xFF && header[1]&0xE0 == 0xE0 && header[1]&0x06 != 0:
+		return SampleFormatMP3
+	}
+	return ""
+}
+
+// sampleDuration returns the duration of MP3 and WAV samples. ok is false for other formats.
+func sampleDuration(format string, data []byte) (d time.Duration, ok bool, err error) {
+	switch format {
+	case SampleFormatMP3:
+		info, err := audio.ParseMP3(data)
+		return info.Duration, true, err
+	case SampleFormatWAV:
+		d, err := wavDuration(data)
+		return d, true, err
+	}
+	return 0, false, nil
+}
+
+// Validate checks the request against a set of limits (typically DefaultVoiceSampleLimits) before it is sent
+// with AddVoice or EditVoice. It checks the name, the labels and the format, size and duration of every sample.
+//
+// Sample files are opened and read in full, and the Readers of Files are read and then seeked back to their
+// start when they implement io.Seeker. Other Readers can only be checked for their format: their first bytes
+// are read and the Reader is replaced with one that returns the same data.
+//
+// It returns nil if no problems were found or a *VoiceValidationError listing all the problems otherwise.
+func (r *AddEditVoiceRequest) Validate(limits VoiceSampleLimits) error {
+	problems := r.validate(limits)
+	if len(problems) > 0 {
+		return &VoiceValidationError{Problems: problems}
+	}
+	return nil
+}
+
+func (r *AddEditVoiceRequest) validate(limits VoiceSampleLimits) []VoiceProblem {
+	var problems []VoiceProblem
+	add := func(field, sample, format string, args ...interface{}) {
+		problems = append(problems, VoiceProblem{Field: field, Sample: sample, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if strings.TrimSpace(r.Name) == "" {
+		add("name", "", "must not be empty")
+	}
+
+	if limits.MaxLabels > 0 && len(r.Labels) > limits.MaxLabels {
+		add("labels", "", "%d labels exceed the maximum of %d", len(r.Labels), limits.MaxLabels)
+	}
+	keys := make([]string, 0, len(r.Labels))
+	for k := range r.Labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v := r.Labels[k]
+		switch {
+		case k == "":
+			add("labels", "", "keys must not be empty")
+		case strings.IndexFunc(k, unicode.IsSpace) >= 0:
+			add("labels", "", "key %q must not contain whitespace", k)
+		case limits.MaxLabelLength > 0 && len(k) > limits.MaxLabelLength:
+			add("labels", "", "key %q exceeds %d characters", k, limits.MaxLabelLength)
+		}
+		switch {
+		case strings.TrimSpace(v) == "":
+			add("labels", "", "value of %q must not be empty", k)
+		case limits.MaxLabelLength > 0 && len(v) > limits.MaxLabelLength:
+			add("labels", "", "value of %q exceeds %d characters", k, limits.MaxLabelLength)
+		}
+	}
+
+	count := len(r.FilePaths) + len(r.Files)
+	if limits.MaxSamples > 0 && count > limits.MaxSamples {
+		add("files", "", "%d samples exceed the maximum of %d", count, limits.MaxSamples)
+	}
+
+	var total int64
+	checkContent := func(name string, size int64, read func() ([]byte, error)) {
+		total += size
+		if limits.MaxFileSize > 0 && size > limits.MaxFileSize {
+			add("files", name, "size of %d bytes exceeds the maximum of %d", size, limits.MaxFileSize)
+			return
+		}
+		data, err := read()
+		if err != nil {
+			add("files", name, "cannot be read: %s", err)
+			return
+		}
+		format := DetectSampleFormat(data)
+		if format == "" {
+			add("files", name, "unsupported audio format")
+			return
+		}
+		d, ok, err := sampleDuration(format, data)
+		switch {
+		case !ok:
+		case err != nil:
+			add("files", name, "invalid %s audio: %s", format, err)
+		case limits.MinDuration > 0 && d < limits.MinDuration:
+			add("files", name, "duration of %s is shorter than the minimum of %s", d, limits.MinDuration)
+		case limits.MaxDuration > 0 && d > limits.MaxDuration:
+			add("files", name, "duration of %s exceeds the maximum of %s", d, limits.MaxDuration)
+		}
+	}
+
+	for _, p := range r.FilePaths {
+		var info fs.FileInfo
+		var err error
+		if r.FS != nil {
+			info, err = fs.Stat(r.FS, p)
+		} else {
+			info, err = os.Stat(p)
+		}
+		if err != nil {
+			add("files", p, "cannot be opened: %s", err)
+			continue
+		}
+		checkContent(p, info.Size(), func() ([]byte, error) {
+			if r.FS != nil {
+				return fs.ReadFile(r.FS, p)
+			}
+			return os.ReadFile(p)
+		})
+	}
+
+	for i := range r.Files {
+		f := &r.Files[i]
+		name := f.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i)
+		}
+		if f.Reader == nil {
+			add("files", name, "has no reader")
+			continue
+		}
+		if s, ok := f.Reader.(io.ReadSeeker); ok {
+			size, err := s.Seek(0, io.SeekEnd)
+			if err == nil {
+				_, err = s.Seek(0, io.SeekStart)
+			}
+			if err != nil {
+				add("files", name, "cannot be read: %s", err)
+				continue
+			}
+			checkContent(name, size, func() ([]byte, error) {
+				data, err := io.ReadAll(s)
+				if _, seekErr := s.Seek(0, io.SeekStart); err == nil {
+					err = seekErr
+				}
+				return data, err
+			})
+			continue
+		}
+		br := bufio.NewReaderSize(f.Reader, 16)
+		f.Reader = br
+		header, err := br.Peek(sniffLen)
+		if err != nil && err != io.EOF {
+			add("files", name, "cannot be read: %s", err)
+			continue
+		}
+		if DetectSampleFormat(header) == "" {
+			add("files", name, "unsupported audio format")
+		}
+	}
+
+	if limits.MaxTotalSize > 0 && total > limits.MaxTotalSize {
+		add("files", "", "total size of %d bytes exceeds the maximum of %d", total, limits.MaxTotalSize)
+	}
+	return problems
+}
+
+// ValidateVoiceRequest validates a voice request locally (see AddEditVoiceRequest.Validate) and against the
+// user's subscription, checking that instant voice cloning is available, that the voice add/edit allowance is
+// not used up and, for new voices, that the voice limit is not reached.
+//
+// It takes a pointer to the AddEditVoiceRequest to be validated, a VoiceSampleLimits argument and a bool
+// argument that is true if the request adds a new voice (AddVoice) rather than editing one (EditVoice).
+//
+// The subscription and voices are looked up in the caches of the client (see SetSubscriptionCacheTTL and
+// CachedVoices).
+//
+// It returns nil if no problems were found, a *VoiceValidationError listing all the problems, or an error if
+// the subscription or voices could not be retrieved.
+func (c *Client) ValidateVoiceRequest(voiceReq *AddEditVoiceRequest, limits VoiceSampleLimits, newVoice bool) error {
+	problems := voiceReq.validate(limits)
+
+	sub, err := c.subscription.get(c.GetSubscription)
+	if err != nil {
+		return err
+	}
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, VoiceProblem{Field: "subscription", Message: fmt.Sprintf(format, args...)})
+	}
+	if !sub.CanUseInstantVoiceCloning && len(voiceReq.FilePaths)+len(voiceReq.Files) > 0 {
+		add("instant voice cloning is not available for the %q tier", sub.Tier)
+	}
+	if sub.MaxVoiceAddEdits > 0 && sub.VoiceAddEditCounter >= sub.MaxVoiceAddEdits {
+		add("all %d voice additions and edits have been used", sub.MaxVoiceAddEdits)
+	}
+	if newVoice && sub.VoiceLimit > 0 {
+		voices, err := c.CachedVoices()
+		if err != nil {
+			return err
+		}
+		own := 0
+		for _, v := range voices {
+			if v.Category != "premade" {
+				own++
+			}
+		}
+		if own >= sub.VoiceLimit {
+			add("voice limit of %d reached", sub.VoiceLimit)
+		}
+	}
+
+	if len(problems) > 0 {
+		return &VoiceValidationError{Problems: problems}
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package elevenlabs_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/haguro/elevenlabs-go"
+)
+
+// testMP3 returns n silent MPEG-1 Layer III frames at 128kbps and 44.1kHz (26.1ms each).
+func testMP3(n int) []byte {
+	frame := make([]byte, 417)
+	copy(frame, []byte{0xFF, 0xFB, 0x90, 0x00})
+	return bytes.Repeat(frame, n)
+}
+
+// testWAV returns d of silent 16kHz PCM audio in a WAV container.
+func testWAV(t *testing.T, d time.Duration) []byte {
+	t.Helper()
+	wav, err := elevenlabs.WrapWAV(make([]byte, int(d.Seconds()*16000)*2), elevenlabs.FormatPCM_16000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return wav
+}
+
+func TestDetectSampleFormat(t *testing.T) {
+	testCases := []struct {
+		header []byte
+		exp    string
+	}{
+		{[]byte("ID3\x04\x00"), elevenlabs.SampleFormatMP3},
+		{[]byte{0xFF, 0xFB, 0x90, 0x00}, elevenlabs.SampleFormatMP3},
+		{[]byte("RIFF\x00\x00\x00\x00WAVE"), elevenlabs.SampleFormatWAV},
+		{[]byte("fLaC\x00"), elevenlabs.SampleFormatFLAC},
+		{[]byte("OggS\x00"), elevenlabs.SampleFormatOgg},
+		{[]byte("\x00\x00\x00\x20ftypM4A "), elevenlabs.SampleFormatMP4},
+		{[]byte{0x1A, 0x45, 0xDF, 0xA3}, elevenlabs.SampleFormatWebM},
+		{[]byte{0xFF, 0xF1, 0x50, 0x80}, elevenlabs.SampleFormatAAC},
+		{[]byte("mp3contenthere"), ""},
+		{[]byte("RIFF\x00\x00\x00\x00AVI "), ""},
+		{nil, ""},
+	}
+	for _, tc := range testCases {
+		if got := elevenlabs.DetectSampleFormat(tc.header); got != tc.exp {
+			t.Errorf("Expected format %q for header %q, got %q", tc.exp, tc.header, got)
+		}
+	}
+}
+
+func TestAddEditVoiceRequestValidate(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.mp3": {Data: testMP3(50)},
+		"b.wav": {Data: testWAV(t, 2*time.Second)},
+	}
+	mp3 := testMP3(50)
+	req := elevenlabs.AddEditVoiceRequest{
+		Name:      "Voice",
+		FS:        fsys,
+		FilePaths: []string{"a.mp3", "b.wav"},
+		Files: []elevenlabs.VoiceFile{
+			{Name: "c.wav", Reader: bytes.NewReader(testWAV(t, 3*time.Second))},
+			{Name: "d.mp3", Reader: io.MultiReader(bytes.NewReader(mp3))},
+		},
+		Labels: map[string]string{"accent": "british"},
+	}
+	if err := req.Validate(elevenlabs.DefaultVoiceSampleLimits); err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+	for i, f := range req.Files {
+		b, _ := io.ReadAll(f.Reader)
+		if len(b) == 0 || (i == 1 && !bytes.Equal(b, mp3)) {
+			t.Errorf("Expected the reader of %q to still return the sample data after validation", f.Name)
+		}
+	}
+
+	req = elevenlabs.AddEditVoiceRequest{
+		Name:      " ",
+		FS:        fsys,
+		FilePaths: []string{"a.mp3", "b.wav", "missing.mp3"},
+		Files: []elevenlabs.VoiceFile{
+			{Name: "short.wav", Reader: bytes.NewReader(testWAV(t, 500*time.Millisecond))},
+			{Name: "text.mp3", Reader: strings.NewReader("not audio at all")},
+			{Name: "stream.mp3", Reader: io.MultiReader(strings.NewReader("not audio"))},
+			{Name: "broken.wav", Reader: bytes.NewReader([]byte("RIFF\x00\x00\x00\x00WAVEjunk"))},
+		},
+		Labels: map[string]string{"bad key": "x", "empty": "", "long": strings.Repeat("x", 51)},
+	}
+	limits := elevenlabs.DefaultVoiceSampleLimits
+	limits.MaxSamples = 6
+	limits.MaxFileSize = 60000
+	limits.MaxTotalSize = 100000
+	limits.MaxLabels = 2
+	err := req.Validate(limits)
+	var valErr *elevenlabs.VoiceValidationError
+	if !errors.As(err, &valErr) {
+		t.Fatalf("Expected error of type %T, got %T: %v", valErr, err, err)
+	}
+	var got []string
+	for _, p := range valErr.Problems {
+		got = append(got, fmt.Sprintf("%s|%s", p.Field, p.Sample))
+	}
+	exp := []string{
+		"name|",
+		"labels|", // too many labels
+		"labels|", // key with whitespace
+		"labels|", // empty value
+		"labels|", // long value
+		"files|",  // too many samples
+		"files|b.wav",
+		"files|missing.mp3",
+		"files|short.wav",
+		"files|text.mp3",
+		"files|stream.mp3",
+		"files|broken.wav",
+		"files|", // total size
+	}
+	if !reflect.DeepEqual(got, exp) {
+		t.Errorf("Expected problems %v, got %v (%q)", exp, got, err)
+	}
+}
+
+func TestValidateVoiceRequest(t *testing.T) {
+	testCases := []struct {
+		name        string
+		sub         string
+		newVoice    bool
+		expProblems int
+	}{
+		{"Within limits", `{"can_use_instant_voice_cloning":true,"voice_limit":3,"max_voice_add_edits":10,"voice_add_edit_counter":2}`, true, 0},
+		{"Edit at voice limit", `{"can_use_instant_voice_cloning":true,"voice_limit":2,"max_voice_add_edits":10}`, false, 0},
+		{"New voice at voice limit", `{"can_use_instant_voice_cloning":true,"voice_limit":2,"max_voice_add_edits":10}`, true, 1},
+		{"New voice without voice limit", `{"can_use_instant_voice_cloning":true,"max_voice_add_edits":10}`, true, 0},
+		{"No cloning and no edits left", `{"voice_limit":10,"max_voice_add_edits":10,"voice_add_edit_counter":10}`, false, 2},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			log := &requestLog{}
+			server := testServer(t, testServerConfig{
+				routes: map[string]http.HandlerFunc{
+					"GET /user/subscription": func(w http.ResponseWriter, r *http.Request) {
+						w.Write([]byte(tc.sub))
+					},
+					"GET /voices": func(w http.ResponseWriter, r *http.Request) {
+						w.Write([]byte(`{"voices":[{"voice_id":"1","category":"premade"},{"voice_id":"2","category":"cloned"},{"voice_id":"3","category":"generated"}]}`))
+					},
+				},
+				log: log,
+			})
+			defer server.Close()
+			client := elevenlabs.NewMockClient(context.Background(), server.URL, mockAPIKey, mockTimeout)
+
+			newReq := func() *elevenlabs.AddEditVoiceRequest {
+				return &elevenlabs.AddEditVoiceRequest{Name: "Voice", Files: []elevenlabs.VoiceFile{{Name: "a.mp3", Reader: bytes.NewReader(testMP3(50))}}}
+			}
+			client.ValidateVoiceRequest(newReq(), elevenlabs.DefaultVoiceSampleLimits, tc.newVoice)
+			err := client.ValidateVoiceRequest(newReq(), elevenlabs.DefaultVoiceSampleLimits, tc.newVoice)
+			if subs, voices := log.count("GET /user/subscription"), log.count("GET /voices"); subs != 1 || voices > 1 {
+				t.Errorf("Expected the subscription and voices to be retrieved at most once, got %d and %d requests", subs, voices)
+			}
+			if tc.expProblems == 0 {
+				if err != nil {
+					t.Errorf("Expected no errors, got %q", err)
+				}
+				return
+			}
+			var valErr *elevenlabs.VoiceValidationError
+			if !errors.As(err, &valErr) || len(valErr.Problems) != tc.expProblems {
+				t.Errorf("Expected %d problems, got %v", tc.expProblems, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,263 @@
+package elevenlabs
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	wavFormatPCM  = 1
+	wavFormatALaw = 6
+	wavFormatULaw = 7
+
+	// wavStreamingSize is used as the RIFF and data chunk sizes when the final sizes are unknown
+	// and the header cannot be fixed up afterwards. Most decoders treat it as "read until EOF".
+	wavStreamingSize = 0xFFFFFFFF
+)
+
+type wavHeader struct {
+	formatTag     uint16
+	channels      uint16
+	sampleRate    uint32
+	bitsPerSample uint16
+}
+
+func newWAVHeader(format AudioFormat) (wavHeader, error) {
+	info, ok := format.Info()
+	if !ok {
+		return wavHeader{}, fmt.Errorf("%w: %q", ErrUnknownAudioFormat, format)
+	}
+	h := wavHeader{channels: 1, sampleRate: uint32(info.SampleRate), bitsPerSample: uint16(info.BytesPerSample * 8)}
+	switch info.Codec {
+	case CodecPCM:
+		h.formatTag = wavFormatPCM
+	case CodecULaw:
+		h.formatTag = wavFormatULaw
+	case CodecALaw:
+		h.formatTag = wavFormatALaw
+	default:
+		return wavHeader{}, fmt.Errorf("%w: %q", ErrUnsupportedWAVFormat, format)
+	}
+	return h, nil
+}
+
+// fmtChunkSize returns the size of the fmt chunk's body. Non-PCM formats carry an extra cbSize field.
+func (h wavHeader) fmtChunkSize() uint32 {
+	if h.formatTag == wavFormatPCM {
+		return 16
+	}
+	return 18
+}
+
+// size returns the total length of the header, i.e. the offset at which audio data starts.
+func (h wavHeader) size() int64 {
+	return 12 + 8 + int64(h.fmtChunkSize()) + 8
+}
+
+// dataSizeOffset returns the offset of the data chunk's size field.
+func (h wavHeader) dataSizeOffset() int64 {
+	return h.size() - 4
+}
+
+func (h wavHeader) bytes(riffSize, dataSize uint32) []byte {
+	b := make([]byte, 0, h.size())
+	blockAlign := h.channels * h.bitsPerSample / 8
+
+	b = append(b, "RIFF"...)
+	b = appendUint32(b, riffSize)
+	b = append(b, "WAVEfmt "...)
+	b = appendUint32(b, h.fmtChunkSize())
+	b = appendUint16(b, h.formatTag)
+	b = appendUint16(b, h.channels)
+	b = appendUint32(b, h.sampleRate)
+	b = appendUint32(b, h.sampleRate*uint32(blockAlign))
+	b = appendUint16(b, blockAlign)
+	b = appendUint16(b, h.bitsPerSample)
+	if h.formatTag != wavFormatPCM {
+		b = appendUint16(b, 0) // cbSize
+	}
+	b = append(b, "data"...)
+	b = appendUint32(b, dataSize)
+	return b
+}
+
+// riffSize returns the value of the RIFF chunk size field for a given amount of audio data.
+func (h wavHeader) riffSize(dataSize uint32) uint32 {
+	return uint32(h.size()) - 8 + dataSize + dataSize%2
+}
+
+// WrapWAV wraps headerless audio returned by TextToSpeech in a RIFF/WAV container.
+//
+// It takes a byte slice containing the raw audio and an AudioFormat argument that represents the output format
+// with which the audio was requested. Only the PCM, μ-law and A-law formats (e.g. FormatPCM_16000 or
+// FormatULaw_8000) can be wrapped.
+//
+// It returns a byte slice containing a complete WAV file, or an error.
+func WrapWAV(audio []byte, format AudioFormat) ([]byte, error) {
+	h, err := newWAVHeader(format)
+	if err != nil {
+		return nil, err
+	}
+	dataSize := uint32(len(audio))
+	b := make([]byte, 0, int(h.size())+len(audio)+1)
+	b = append(b, h.bytes(h.riffSize(dataSize), dataSize)...)
+	b = append(b, audio...)
+	if dataSize%2 == 1 {
+		b = append(b, 0)
+	}
+	return b, nil
+}
+
+// WAVWriter is an io.Writer that wraps headerless audio written to it in a RIFF/WAV container. It is meant to
+// be passed to TextToSpeechStream when requesting one of the PCM, μ-law or A-law output formats.
+//
+// If the underlying writer is an io.WriteSeeker (e.g. an *os.File), the header sizes are fixed up when the
+// WAVWriter is closed. Otherwise, a streaming-friendly header with unspecified sizes is written instead.
+//
+// The NewWAVWriter function should be used when instantiating a new WAVWriter.
+type WAVWriter struct {
+	w             io.Writer
+	header        wavHeader
+	seeker        io.WriteSeeker
+	start         int64
+	headerWritten bool
+	dataSize      uint32
+	closed        bool
+}
+
+// NewWAVWriter creates and returns a new WAVWriter that writes to w.
+//
+// It takes an io.Writer argument to which the WAV file will be written and an AudioFormat argument that
+// represents the output format of the audio that will be written to the WAVWriter.
+//
+// It returns a pointer to a newly created WAVWriter, or an error if the format cannot be wrapped.
+func NewWAVWriter(w io.Writer, format AudioFormat) (*WAVWriter, error) {
+	h, err := newWAVHeader(format)
+	if err != nil {
+		return nil, err
+	}
+	ww := &WAVWriter{w: w, header: h}
+	if s, ok := w.(io.WriteSeeker); ok {
+		// Pipes and terminals implement io.Seeker but fail to seek.
+		if start, err := s.Seek(0, io.SeekCurrent); err == nil {
+			ww.seeker = s
+			ww.start = start
+		}
+	}
+	return ww, nil
+}
+
+func (ww *WAVWriter) writeHeader() error {
+	if ww.headerWritten {
+		return nil
+	}
+	ww.headerWritten = true
+	riffSize, dataSize := uint32(wavStreamingSize), uint32(wavStreamingSize)
+	if ww.seeker != nil {
+		riffSize, dataSize = ww.header.riffSize(0), 0
+	}
+	_, err := ww.w.Write(ww.header.bytes(riffSize, dataSize))
+	return err
+}
+
+// Write writes audio data to the underlying writer, preceded by the WAV header on the first call.
+func (ww *WAVWriter) Write(p []byte) (int, error) {
+	if ww.closed {
+		return 0, ErrWAVWriterClosed
+	}
+	if err := ww.writeHeader(); err != nil {
+		return 0, err
+	}
+	n, err := ww.w.Write(p)
+	ww.dataSize += uint32(n)
+	return n, err
+}
+
+// Close finalizes the WAV file. It writes the header if no audio was written and, if the underlying writer
+// supports seeking, pads the data chunk and fixes up the RIFF and data chunk sizes.
+//
+// Close does not close the underlying writer.
+func (ww *WAVWriter) Close() error {
+	if ww.closed {
+		return nil
+	}
+	if err := ww.writeHeader(); err != nil {
+		return err
+	}
+	ww.closed = true
+	if ww.seeker == nil {
+		return nil
+	}
+
+	if ww.dataSize%2 == 1 {
+		if _, err := ww.w.Write([]byte{0}); err != nil {
+			return err
+		}
+	}
+	end, err := ww.seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	b := make([]byte, 4)
+	fixups := []struct {
+		offset int64
+		value  uint32
+	}{
+		{4, ww.header.riffSize(ww.dataSize)},
+		{ww.header.dataSizeOffset(), ww.dataSize},
+	}
+	for _, f := range fixups {
+		if _, err := ww.seeker.Seek(ww.start+f.offset, io.SeekStart); err != nil {
+			return err
+		}
+		binary.LittleEndian.PutUint32(b, f.value)
+		if _, err := ww.seeker.Write(b); err != nil {
+			return err
+		}
+	}
+	_, err = ww.seeker.Seek(end, io.SeekStart)
+	return err
+}
+
+// appendUint16 and appendUint32 append little-endian integers to b (binary.LittleEndian.AppendUint* requires
+// Go 1.19).
+func appendUint16(b []byte, v uint16) []byte {
+	return append(b, byte(v), byte(v>>8))
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	return append(b, byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
+}
+
+// wavDuration returns the duration of WAV audio by reading the byte rate of its fmt chunk and the size of its
+// data chunk. A streaming size (see wavStreamingSize) is replaced with the length of the remaining data.
+func wavDuration(data []byte) (time.Duration, error) {
+	le := binary.LittleEndian
+	if len(data) < 12 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+		return 0, fmt.Errorf("not a WAV file")
+	}
+	var byteRate uint32
+	for off := 12; off+8 <= len(data); {
+		id, size := string(data[off:off+4]), le.Uint32(data[off+4:off+8])
+		body := off + 8
+		switch id {
+		case "fmt ":
+			if body+16 > len(data) {
+				return 0, fmt.Errorf("truncated WAV fmt chunk")
+			}
+			byteRate = le.Uint32(data[body+8 : body+12])
+		case "data":
+			if byteRate == 0 {
+				return 0, fmt.Errorf("WAV data chunk without a valid fmt chunk")
+			}
+			if remaining := uint32(len(data) - body); size > remaining {
+				size = remaining
+			}
+			return time.Duration(int64(size) * int64(time.Second) / int64(byteRate)), nil
+		}
+		off = body + int(size) + int(size&1)
+	}
+	return 0, fmt.Errorf("WAV data chunk not found")
+}
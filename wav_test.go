@@ -0,0 +1,130 @@
+package elevenlabs_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/haguro/elevenlabs-go"
+)
+
+func checkWAVHeader(t *testing.T, b []byte, formatTag uint16, sampleRate, riffSize, dataSize uint32) {
+	t.Helper()
+	le := binary.LittleEndian
+	if len(b) < 44 {
+		t.Fatalf("Expected at least 44 bytes of WAV data, got %d", len(b))
+	}
+	if string(b[0:4]) != "RIFF" || string(b[8:16]) != "WAVEfmt " {
+		t.Fatalf("Expected RIFF/WAVE header, got %q", b[:16])
+	}
+	if got := le.Uint32(b[4:8]); got != riffSize {
+		t.Errorf("Expected RIFF size %d, got %d", riffSize, got)
+	}
+	if got := le.Uint16(b[20:22]); got != formatTag {
+		t.Errorf("Expected format tag %d, got %d", formatTag, got)
+	}
+	if got := le.Uint32(b[24:28]); got != sampleRate {
+		t.Errorf("Expected sample rate %d, got %d", sampleRate, got)
+	}
+	dataOffset := 20 + int(le.Uint32(b[16:20]))
+	if string(b[dataOffset:dataOffset+4]) != "data" {
+		t.Fatalf("Expected data chunk at offset %d, got %q", dataOffset, b[dataOffset:dataOffset+4])
+	}
+	if got := le.Uint32(b[dataOffset+4 : dataOffset+8]); got != dataSize {
+		t.Errorf("Expected data size %d, got %d", dataSize, got)
+	}
+}
+
+func TestWrapWAV(t *testing.T) {
+	pcm := []byte{1, 2, 3, 4, 5, 6}
+	wav, err := elevenlabs.WrapWAV(pcm, elevenlabs.FormatPCM_22050)
+	if err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+	checkWAVHeader(t, wav, 1, 22050, 36+6, 6)
+	if !bytes.Equal(wav[44:], pcm) {
+		t.Errorf("Expected audio data %v after the header, got %v", pcm, wav[44:])
+	}
+
+	ulaw := []byte{0xff, 0x7f, 0x00}
+	wav, err = elevenlabs.WrapWAV(ulaw, elevenlabs.FormatULaw_8000)
+	if err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+	checkWAVHeader(t, wav, 7, 8000, 38+4, 3)
+	if len(wav) != 46+4 {
+		t.Errorf("Expected odd sized data chunk to be padded, got total length %d", len(wav))
+	}
+
+	if _, err := elevenlabs.WrapWAV(pcm, elevenlabs.FormatMP3_44100_128); !errors.Is(err, elevenlabs.ErrUnsupportedWAVFormat) {
+		t.Errorf("Expected error %q, got %v", elevenlabs.ErrUnsupportedWAVFormat, err)
+	}
+}
+
+func TestWAVWriterNonSeekable(t *testing.T) {
+	b := bytes.Buffer{}
+	w, err := elevenlabs.NewWAVWriter(&b, elevenlabs.FormatPCM_16000)
+	if err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+	for _, chunk := range [][]byte{{1, 2}, {3, 4, 5, 6}} {
+		if _, err := w.Write(chunk); err != nil {
+			t.Fatalf("Expected no errors, got %q", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+	checkWAVHeader(t, b.Bytes(), 1, 16000, 0xFFFFFFFF, 0xFFFFFFFF)
+	if !bytes.Equal(b.Bytes()[44:], []byte{1, 2, 3, 4, 5, 6}) {
+		t.Errorf("Unexpected audio data %v", b.Bytes()[44:])
+	}
+	if _, err := w.Write([]byte{0}); !errors.Is(err, elevenlabs.ErrWAVWriterClosed) {
+		t.Errorf("Expected error %q, got %v", elevenlabs.ErrWAVWriterClosed, err)
+	}
+}
+
+func TestWAVWriterTextToSpeechStream(t *testing.T) {
+	audio := []byte("pcm-audio-bytes")
+	server := testServer(t, testServerConfig{
+		expectedMethod:      http.MethodPost,
+		expectedContentType: contentTypeJSON,
+		expectedQueryStr:    "output_format=pcm_24000",
+		statusCode:          http.StatusOK,
+		responseBody:        audio,
+	})
+	defer server.Close()
+	client := elevenlabs.NewMockClient(context.Background(), server.URL, mockAPIKey, mockTimeout)
+
+	path := filepath.Join(t.TempDir(), "out.wav")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	w, err := elevenlabs.NewWAVWriter(f, elevenlabs.FormatPCM_24000)
+	if err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+	err = client.TextToSpeechStream(w, "voiceID", elevenlabs.TextToSpeechRequest{Text: "Test text"}, elevenlabs.OutputFormat(elevenlabs.FormatPCM_24000))
+	if err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Expected no errors, got %q", err)
+	}
+
+	wav, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp, _ := elevenlabs.WrapWAV(audio, elevenlabs.FormatPCM_24000)
+	if !bytes.Equal(wav, exp) {
+		t.Errorf("Expected streamed WAV file to match WrapWAV output.\nExpected:\n%v\nGot:\n%v", exp, wav)
+	}
+}